@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jjcinaz/sheeptabulator/sheep"
+)
+
+const editHelp = `Commands (press Enter after each):
+  m <to> <from>...  merge answers into answer <to>
+  r <n> <text>      rename answer n
+  s <n>             split answer n back into the answers as read
+  b <n>             blank answer n so it scores nothing
+  f <n>             flag answer n for review, or clear the flag
+  u                 undo the last change
+  n, p, g <q>       next, previous, or go to question q
+  w                 save the decisions to the alias file
+  q                 quit
+  ?                 show this help
+`
+
+// editSession walks the quiz master through the answers to each question, recording every
+// decision in the aliases and rescoring the quiz after each one
+type editSession struct {
+	quiz        *sheep.Quiz
+	aliases     *sheep.Aliases
+	filename    string
+	idxQ        int
+	answers     []sheep.PopulationCount // answers to the current question as numbered on screen
+	undo        []*sheep.Aliases
+	unsaved     bool
+	confirmQuit bool
+	phonetic    []int // questions whose answers which sound alike are merged, from -phonetic
+	out         io.Writer
+}
+
+// runNormalizeEdit runs an interactive session to normalize the answers of a quiz
+func runNormalizeEdit(fs *flag.FlagSet, args []string) int {
+	var qf quizFlags
+	qf.register(fs)
+	question := fs.Int("q", 1, "Question number to start at")
+	if code := parseFlags(fs, args); code >= 0 {
+		return code
+	}
+	if !qf.requireFile(fs) {
+		return exitUsage
+	}
+	quiz, err := qf.load()
+	if err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	if *question < 1 || *question > len(quiz.Questions) {
+		fmt.Printf("-q must be between 1 and %d\n", len(quiz.Questions))
+		return exitUsage
+	}
+	filename := qf.aliasFile()
+	if len(filename) == 0 {
+		fmt.Printf("-aliases is required to save decisions for %s\n", qf.filenames[0])
+		return exitUsage
+	}
+	aliases, err := sheep.ReadAliases(filename)
+	if err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	phonetic, err := parseNumbers("-phonetic", qf.phonetic, len(quiz.Questions))
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	s := &editSession{quiz: quiz, aliases: aliases, filename: filename, idxQ: *question - 1, phonetic: phonetic, out: os.Stdout}
+	if err = s.run(os.Stdin); err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	return exitOK
+}
+
+func (s *editSession) run(in io.Reader) error {
+	if err := s.rescore(); err != nil {
+		return err
+	}
+	fmt.Fprint(s.out, editHelp)
+	s.show()
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(s.out, "> ")
+		if !scanner.Scan() {
+			break
+		}
+		redraw, quit, err := s.do(strings.TrimSpace(scanner.Text()))
+		if err != nil {
+			fmt.Fprintln(s.out, err)
+		}
+		if quit {
+			return nil
+		}
+		if redraw {
+			s.show()
+		}
+	}
+	if s.unsaved {
+		fmt.Fprintf(s.out, "\nQuit without saving\n")
+	}
+	return scanner.Err()
+}
+
+// do carries out one command, reporting whether the question should be shown again and
+// whether the session is over
+func (s *editSession) do(line string) (redraw, quit bool, err error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false, false, nil
+	}
+	if fields[0] != "q" {
+		s.confirmQuit = false
+	}
+	key := s.aliases.QuestionKey(s.quiz, s.idxQ)
+	switch fields[0] {
+	case "m":
+		if len(fields) < 3 {
+			return false, false, fmt.Errorf("usage: m <to> <from>...")
+		}
+		to, err := s.answer(fields[1])
+		if err != nil {
+			return false, false, err
+		}
+		from := make([]string, 0, len(fields)-2)
+		for _, f := range fields[2:] {
+			a, err := s.answer(f)
+			if err != nil {
+				return false, false, err
+			}
+			from = append(from, a)
+		}
+		return true, false, s.change(func() {
+			for _, a := range from {
+				s.aliases.Add(key, a, to)
+			}
+		})
+	case "r":
+		if len(fields) < 3 {
+			return false, false, fmt.Errorf("usage: r <n> <text>")
+		}
+		a, err := s.answer(fields[1])
+		if err != nil {
+			return false, false, err
+		}
+		return true, false, s.change(func() { s.aliases.Add(key, a, strings.Join(fields[2:], " ")) })
+	case "s", "b", "f":
+		if len(fields) != 2 {
+			return false, false, fmt.Errorf("usage: %s <n>", fields[0])
+		}
+		a, err := s.answer(fields[1])
+		if err != nil {
+			return false, false, err
+		}
+		switch fields[0] {
+		case "s":
+			undo := s.aliases.Clone()
+			if variants := s.aliases.Split(key, a); len(variants) == 0 {
+				return false, false, fmt.Errorf("no other answers were merged into '%s'", a)
+			}
+			s.undo = append(s.undo, undo)
+			s.unsaved = true
+			return true, false, s.rescore()
+		case "b":
+			return true, false, s.change(func() { s.aliases.Add(key, a, "") })
+		default:
+			return true, false, s.change(func() { s.aliases.ToggleFlag(key, a) })
+		}
+	case "u":
+		if len(s.undo) == 0 {
+			return false, false, fmt.Errorf("nothing to undo")
+		}
+		s.aliases = s.undo[len(s.undo)-1]
+		s.undo = s.undo[:len(s.undo)-1]
+		s.unsaved = true
+		return true, false, s.rescore()
+	case "n", "p", "g":
+		idxQ := s.idxQ
+		switch fields[0] {
+		case "n":
+			idxQ++
+		case "p":
+			idxQ--
+		default:
+			if len(fields) != 2 {
+				return false, false, fmt.Errorf("usage: g <q>")
+			}
+			n, _ := strconv.Atoi(fields[1])
+			idxQ = n - 1
+		}
+		if idxQ < 0 || idxQ >= len(s.quiz.Questions) {
+			return false, false, fmt.Errorf("questions are numbered 1 to %d", len(s.quiz.Questions))
+		}
+		s.idxQ = idxQ
+		return true, false, nil
+	case "w":
+		if err := s.aliases.Save(s.filename); err != nil {
+			return false, false, err
+		}
+		s.unsaved = false
+		fmt.Fprintf(s.out, "Saved to %s\n", s.filename)
+		return false, false, nil
+	case "q":
+		if s.unsaved && !s.confirmQuit {
+			s.confirmQuit = true
+			return false, false, fmt.Errorf("there are unsaved changes, w to save them or q again to quit")
+		}
+		return false, true, nil
+	case "?":
+		fmt.Fprint(s.out, editHelp)
+		return false, false, nil
+	}
+	return false, false, fmt.Errorf("unknown command '%s', ? for help", fields[0])
+}
+
+// answer returns the answer numbered n on screen
+func (s *editSession) answer(n string) (string, error) {
+	i, err := strconv.Atoi(n)
+	if err != nil || i < 1 || i > len(s.answers) {
+		return "", fmt.Errorf("answers are numbered 1 to %d, not '%s'", len(s.answers), n)
+	}
+	return s.answers[i-1].OriginalAnswer, nil
+}
+
+// change makes a change to the aliases which can be undone, and rescores the quiz
+func (s *editSession) change(f func()) error {
+	s.undo = append(s.undo, s.aliases.Clone())
+	f()
+	s.unsaved = true
+	return s.rescore()
+}
+
+// rescore applies the aliases again, merging the answers which sound alike as scoring does
+// with -phonetic, without adding those merges to the aliases which are saved
+func (s *editSession) rescore() error {
+	if len(s.phonetic) > 0 {
+		if _, err := s.quiz.MergeSoundAlike(s.aliases, s.phonetic); err != nil {
+			return err
+		}
+	} else if _, err := s.quiz.ApplyAliases(s.aliases); err != nil {
+		return err
+	}
+	s.quiz.CalcScores()
+	return nil
+}
+
+// show prints the answers to the current question with the spellings merged into each,
+// and the leading players.  The entries of answers to a multi-answer question are shown
+// on their own.
+func (s *editSession) show() {
+	question := &s.quiz.Questions[s.idxQ]
+	key := s.aliases.QuestionKey(s.quiz, s.idxQ)
+	s.answers = question.SortedCounts(false)
+	merged := make(map[string]map[string]bool)
+	blanked := make(map[string]bool)
+	for _, r := range s.quiz.Responses {
+		if s.idxQ >= len(r.RawAnswers) || len(r.RawAnswers[s.idxQ]) == 0 {
+			continue
+		}
+		raws, answers := s.quiz.NormalizedEntries(s.idxQ, r.RawAnswers[s.idxQ])
+		for i, raw := range raws {
+			if len(answers[i]) == 0 {
+				blanked[raw] = true
+				continue
+			}
+			a := s.quiz.AnswerKey(s.idxQ, answers[i])
+			if merged[a] == nil {
+				merged[a] = make(map[string]bool)
+			}
+			merged[a][raw] = true
+		}
+	}
+	fmt.Fprintf(s.out, "\nQuestion #%d of %d -- %s\n", s.idxQ+1, len(s.quiz.Questions), question.Text)
+	for i, p := range s.answers {
+		line := fmt.Sprintf("\t%3d.\t%3d\t%s", i+1, p.Freq, p.OriginalAnswer)
+		if s.aliases.IsFlagged(key, p.OriginalAnswer) {
+			line += "  [flagged]"
+		}
+		var spellings []string
+		for _, raw := range sortedKeys(merged[s.quiz.AnswerKey(s.idxQ, p.OriginalAnswer)]) {
+			if raw != p.OriginalAnswer {
+				spellings = append(spellings, raw)
+			}
+		}
+		if len(spellings) > 0 {
+			line += "  (" + strings.Join(spellings, " | ") + ")"
+		}
+		fmt.Fprintln(s.out, line)
+	}
+	if len(blanked) > 0 {
+		fmt.Fprintf(s.out, "\tBlanked: %s\n", strings.Join(sortedKeys(blanked), " | "))
+	}
+	leaders := s.quiz.PlayerScores()
+	if len(leaders) > 3 {
+		leaders = leaders[:3]
+	}
+	top := make([]string, 0, len(leaders))
+	for _, m := range leaders {
+		top = append(top, fmt.Sprintf("%s %d", m.Name, m.Score))
+	}
+	fmt.Fprintf(s.out, "Leaders: %s\n", strings.Join(top, ", "))
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jjcinaz/sheeptabulator/sheep"
+)
+
+func newEditQuiz(answers ...[]string) *sheep.Quiz {
+	q := &sheep.Quiz{Questions: []sheep.Question{{Text: "A color"}, {Text: "📋 Three fruits", Multi: true}}}
+	for i, a := range answers {
+		name := string(rune('A' + i))
+		q.Responses = append(q.Responses, sheep.Response{Email: name + "@acme.com", Name: name, Answers: a, AnswerScore: make([]int, len(a))})
+	}
+	return q
+}
+
+func TestEditSession(t *testing.T) {
+	quiz := newEditQuiz(
+		[]string{"Red", "Apple, Banana"},
+		[]string{"red.", "apple; Bananna"},
+		[]string{"Blue", "Cherry\nBanana"},
+		[]string{"Bleu", ""},
+		[]string{"Green", ""},
+		[]string{"Purple", ""},
+	)
+	filename := filepath.Join(t.TempDir(), "quiz.aliases.json")
+	var out bytes.Buffer
+	s := &editSession{quiz: quiz, aliases: &sheep.Aliases{}, filename: filename, out: &out}
+	script := []string{"m 3 2", "r 4 Violet", "s 1", "m 3 2", "b 3", "f 2", "u", "g 2", "m 2 3", "w", "q"}
+	if err := s.run(strings.NewReader(strings.Join(script, "\n") + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"1.\t  2\tRed  (red.)",
+		"4.\t  1\tViolet  (Purple)",
+		"5.\t  1\tViolet  (Purple)", // after the split
+		"Blanked: Green",
+		"2.\t  2\tRed  [flagged]  (red.)",
+		"1.\t  2\tApple  (apple)", // entries of a multi-answer question are shown on their own
+		"1.\t  3\tBanana  (Bananna)",
+		"Saved to " + filename,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output does not contain %q:\n%s", want, out.String())
+		}
+	}
+	got, err := sheep.ReadAliases(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]map[string]string{"1": {"Bleu": "Blue", "Green": "", "Purple": "Violet"}, "2": {"Bananna": "Banana"}}
+	if !reflect.DeepEqual(got.Questions, want) || len(got.Flagged) != 0 {
+		t.Errorf("saved aliases = %v, flagged %v, want %v", got.Questions, got.Flagged, want)
+	}
+}
+
+func TestEditSession_unsaved(t *testing.T) {
+	var out bytes.Buffer
+	s := &editSession{quiz: newEditQuiz([]string{"Red", ""}, []string{"Blue", ""}), aliases: &sheep.Aliases{}, out: &out}
+	if err := s.run(strings.NewReader("b 1\nx\nq\nq\nb 2\n")); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"unknown command 'x'", "there are unsaved changes"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output does not contain %q:\n%s", want, out.String())
+		}
+	}
+	if len(s.undo) != 1 {
+		t.Errorf("the session went on after the second q")
+	}
+}
+
+func TestEditSession_phonetic(t *testing.T) {
+	quiz := newEditQuiz(
+		[]string{"George Clooney", ""},
+		[]string{"George Clooney", ""},
+		[]string{"Jorge Cluny", ""},
+	)
+	filename := filepath.Join(t.TempDir(), "quiz.aliases.json")
+	var out bytes.Buffer
+	s := &editSession{quiz: quiz, aliases: &sheep.Aliases{}, filename: filename, phonetic: []int{0}, out: &out}
+	if err := s.run(strings.NewReader("w\nq\n")); err != nil {
+		t.Fatal(err)
+	}
+	// The sound merge is kept in the scores as with score -phonetic, but not saved
+	if !strings.Contains(out.String(), "1.\t  3\tGeorge Clooney  (Jorge Cluny)") {
+		t.Errorf("output does not show the sound merge:\n%s", out.String())
+	}
+	got, err := sheep.ReadAliases(filename)
+	if err != nil || len(got.Questions) != 0 {
+		t.Errorf("saved aliases = %v, %v", got.Questions, err)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/jjcinaz/sheeptabulator/sheep"
+)
+
+// runExportNormalized writes a workbook with the normalized answers, so the changes can be
+// audited or shared
+func runExportNormalized(fs *flag.FlagSet, args []string) int {
+	var qf quizFlags
+	qf.register(fs)
+	output := fs.String("o", "", "XLSX file to write (default: <file>.normalized.xlsx)")
+	if code := parseFlags(fs, args); code >= 0 {
+		return code
+	}
+	if !qf.requireFile(fs) {
+		return exitUsage
+	}
+	if len(*output) == 0 {
+		*output = sheep.NormalizedFileName(qf.filenames[0])
+		if len(*output) == 0 {
+			fmt.Printf("-o is required to export %s\n", qf.filenames[0])
+			return exitUsage
+		}
+	}
+	quiz, err := qf.load()
+	if err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	opts, err := qf.readOptions()
+	if err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	// Only a single workbook can be copied; merged responses go to a new workbook
+	src := ""
+	if len(qf.filenames) == 1 {
+		src = qf.filenames[0]
+	}
+	n, err := quiz.ExportNormalized(src, *output, opts)
+	if err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	fmt.Printf("Wrote %d changed answers to %s\n", n, *output)
+	return exitOK
+}
@@ -5,6 +5,7 @@ go 1.21.7
 require (
 	github.com/xuri/excelize/v2 v2.8.1
 	golang.org/x/oauth2 v0.18.0
+	golang.org/x/text v0.14.0
 	google.golang.org/api v0.170.0
 )
 
@@ -33,7 +34,6 @@ require (
 	golang.org/x/crypto v0.21.0 // indirect
 	golang.org/x/net v0.22.0 // indirect
 	golang.org/x/sys v0.18.0 // indirect
-	golang.org/x/text v0.14.0 // indirect
 	google.golang.org/appengine v1.6.8 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240311132316-a219d84964c2 // indirect
 	google.golang.org/grpc v1.62.1 // indirect
@@ -16,11 +16,10 @@ import (
 )
 
 // Retrieve a token, saves the token, then returns the generated client.
-func getClient(config *oauth2.Config) *http.Client {
-	// The file token.json stores the user's access and refresh tokens, and is
+func getClient(config *oauth2.Config, tokFile string) *http.Client {
+	// The token file stores the user's access and refresh tokens, and is
 	// created automatically when the authorization flow completes for the first
 	// time.
-	tokFile := "token.json"
 	tok, err := tokenFromFile(tokFile)
 	if err != nil {
 		tok = getTokenFromWeb(config)
@@ -70,31 +69,41 @@ func saveToken(path string, token *oauth2.Token) {
 	json.NewEncoder(f).Encode(token)
 }
 
+// clientFromCredentials returns an OAuth client for the scope using the application
+// credentials in credentials.json.  Each scope keeps its own token file.
+func clientFromCredentials(scope string, tokFile string) (*http.Client, error) {
+	b, err := ioutil.ReadFile("credentials.json")
+	if err != nil {
+		return nil, fmt.Errorf("unable to read credentials.json: %s", err)
+	}
+	config, err := google.ConfigFromJSON(b, scope)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse credentials.json: %s", err)
+	}
+	return getClient(config, tokFile), nil
+}
+
 var (
 	gapiSvcInit sync.Once
 	gapiSvc     *people.Service
+	gapiSvcErr  error
 )
 
 func GetName(email string) (string, error) {
 	var err error
 	gapiSvcInit.Do(func() {
-		var (
-			b      []byte
-			config *oauth2.Config
-		)
-		if b, err = ioutil.ReadFile("credentials.json"); err != nil {
-			log.Fatalf("unable to read credentials.json: %s", err.Error())
+		var client *http.Client
+		if client, gapiSvcErr = clientFromCredentials(people.DirectoryReadonlyScope, "token.json"); gapiSvcErr != nil {
+			return
 		}
-		config, err = google.ConfigFromJSON(b, people.DirectoryReadonlyScope)
-		if err != nil {
-			log.Fatalf("unable to People client: %s", err.Error())
-		}
-		client := getClient(config)
-		gapiSvc, err = people.NewService(context.Background(), option.WithHTTPClient(client))
-		if err != nil {
-			log.Fatalf("unable to create GAPI service: %s", err.Error())
+		gapiSvc, gapiSvcErr = people.NewService(context.Background(), option.WithHTTPClient(client))
+		if gapiSvcErr != nil {
+			gapiSvcErr = fmt.Errorf("unable to create GAPI service: %s", gapiSvcErr)
 		}
 	})
+	if gapiSvcErr != nil {
+		return "", gapiSvcErr
+	}
 	var r *people.ListDirectoryPeopleResponse
 	for {
 		pagetoken := ""
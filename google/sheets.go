@@ -0,0 +1,122 @@
+package google
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jjcinaz/sheeptabulator/sheep"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+// SheetsScheme starts the name of an input read with the Sheets API:
+// sheets://<spreadsheetId>/<range>.  The range is optional; without one the sheets of the
+// spreadsheet are chosen the same way as the sheets of a workbook.
+const SheetsScheme = "sheets://"
+
+// SheetsReader reads the responses of a Google Form straight from the Google Sheet which
+// collects them.  Register it with sheep.RegisterReader.
+type SheetsReader struct {
+	// Options for the Sheets service.  When empty, the OAuth client from credentials.json
+	// is used, with the token saved in token-sheets.json.
+	Options []option.ClientOption
+}
+
+func (*SheetsReader) Name() string { return "Google Sheets API" }
+
+func (*SheetsReader) Claim(filename string, head []byte) bool {
+	return strings.HasPrefix(filename, SheetsScheme)
+}
+
+func (r *SheetsReader) Read(filename string, opts sheep.ReadOptions) ([]sheep.Question, []sheep.Response, error) {
+	id, rng, err := parseSheetsName(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx := context.Background()
+	svc, err := r.service(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rng) > 0 {
+		rows, err := getSheetRows(ctx, svc, id, rng)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sheep.ParseRows(rows, opts)
+	}
+	ss, err := svc.Spreadsheets.Get(id).Fields("sheets.properties.title").Context(ctx).Do()
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get spreadsheet %s: %s", id, err)
+	}
+	titles := make([]string, 0, len(ss.Sheets))
+	for _, s := range ss.Sheets {
+		titles = append(titles, s.Properties.Title)
+	}
+	return sheep.ReadSheets(titles, opts, func(sheet string) ([][]string, error) {
+		return getSheetRows(ctx, svc, id, quoteSheetName(sheet))
+	})
+}
+
+func (r *SheetsReader) service(ctx context.Context) (*sheets.Service, error) {
+	if len(r.Options) > 0 {
+		return sheets.NewService(ctx, r.Options...)
+	}
+	client, err := clientFromCredentials(sheets.SpreadsheetsReadonlyScope, "token-sheets.json")
+	if err != nil {
+		return nil, err
+	}
+	return sheets.NewService(ctx, option.WithHTTPClient(client))
+}
+
+// parseSheetsName splits sheets://<spreadsheetId>/<range> into the id and the range
+func parseSheetsName(filename string) (id, rng string, err error) {
+	rest := strings.TrimPrefix(filename, SheetsScheme)
+	id, rng, _ = strings.Cut(rest, "/")
+	if len(id) == 0 {
+		return "", "", fmt.Errorf("%s has no spreadsheet id, expect %s<spreadsheetId>/<range>", filename, SheetsScheme)
+	}
+	if unescaped, err := url.PathUnescape(rng); err == nil {
+		rng = unescaped
+	}
+	return id, rng, nil
+}
+
+// quoteSheetName makes a sheet name into a range for the whole sheet
+func quoteSheetName(sheet string) string {
+	return "'" + strings.ReplaceAll(sheet, "'", "''") + "'"
+}
+
+// getSheetRows gets the unformatted cell values of a range.  Times come back as Excel
+// style serial numbers, which sheep reads the same way as a spreadsheet's raw cell values.
+func getSheetRows(ctx context.Context, svc *sheets.Service, id, rng string) ([][]string, error) {
+	vr, err := svc.Spreadsheets.Values.Get(id, rng).
+		ValueRenderOption("UNFORMATTED_VALUE").
+		DateTimeRenderOption("SERIAL_NUMBER").
+		MajorDimension("ROWS").
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get %s of spreadsheet %s: %s", rng, id, err)
+	}
+	rows := make([][]string, 0, len(vr.Values))
+	for _, values := range vr.Values {
+		row := make([]string, len(values))
+		for i, v := range values {
+			switch v := v.(type) {
+			case string:
+				row[i] = v
+			case float64:
+				row[i] = strconv.FormatFloat(v, 'f', -1, 64)
+			case nil:
+			default:
+				row[i] = fmt.Sprint(v)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
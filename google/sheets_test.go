@@ -0,0 +1,110 @@
+package google
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jjcinaz/sheeptabulator/sheep"
+	"google.golang.org/api/option"
+)
+
+// newSheetsStub stands in for the Sheets API with one spreadsheet, "quiz1", which has a
+// sheet of notes and a sheet of Google Forms responses
+func newSheetsStub(t *testing.T) *httptest.Server {
+	values := map[string][][]interface{}{
+		"'Notes'": {{"Nothing to see here"}},
+		"'Form Responses 1'": {
+			{"Timestamp", "Email Address", "Your Name", "1. A sweetener", "2. 🎯 A number [7]"},
+			{45366.41666666666, "JJC@acme.com", "Jim Croche", "Sugar", 7},
+			{45366.42, "bhf@acme.com", "Bill Fettman", "sugar"},
+		},
+	}
+	values["Form Responses 1!A1:E3"] = values["'Form Responses 1'"]
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v4/spreadsheets/quiz1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sheets": []interface{}{
+				map[string]interface{}{"properties": map[string]interface{}{"title": "Notes"}},
+				map[string]interface{}{"properties": map[string]interface{}{"title": "Form Responses 1"}},
+			},
+		})
+	})
+	mux.HandleFunc("/v4/spreadsheets/quiz1/values/", func(w http.ResponseWriter, r *http.Request) {
+		rng := strings.TrimPrefix(r.URL.Path, "/v4/spreadsheets/quiz1/values/")
+		if r.URL.Query().Get("valueRenderOption") != "UNFORMATTED_VALUE" {
+			t.Errorf("valueRenderOption = %s", r.URL.Query().Get("valueRenderOption"))
+		}
+		v, ok := values[rng]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": map[string]interface{}{"code": 400, "message": "Unable to parse range: " + rng},
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"range": rng, "majorDimension": "ROWS", "values": v})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSheetsReader(t *testing.T) {
+	srv := newSheetsStub(t)
+	reader := &SheetsReader{Options: []option.ClientOption{
+		option.WithEndpoint(srv.URL + "/"),
+		option.WithHTTPClient(srv.Client()),
+	}}
+	tests := []struct {
+		name     string
+		filename string
+		sheet    string
+		wantErr  string
+	}{
+		{"range", "sheets://quiz1/Form%20Responses%201!A1:E3", "", ""},
+		{"unescaped range", "sheets://quiz1/Form Responses 1!A1:E3", "", ""},
+		{"find sheet", "sheets://quiz1", "", ""},
+		{"named sheet", "sheets://quiz1/", "Form Responses 1", ""},
+		{"wrong sheet", "sheets://quiz1", "Notes", "do not match"},
+		{"bad range", "sheets://quiz1/Sheet9!A:B", "", "Unable to parse range"},
+		{"no id", "sheets://", "", "no spreadsheet id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reader.Claim(tt.filename, nil) {
+				t.Fatalf("Claim(%s) = false", tt.filename)
+			}
+			questions, responses, err := reader.Read(tt.filename, sheep.ReadOptions{Sheet: tt.sheet})
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Read() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if len(questions) != 2 || questions[0].Text != "A sweetener" || strings.Join(questions[1].BonusAnswers, "|") != "7" {
+				t.Errorf("questions = %+v", questions)
+			}
+			if len(responses) != 2 {
+				t.Fatalf("responses = %+v", responses)
+			}
+			r := responses[0]
+			if r.Email != "jjc@acme.com" || r.Name != "Jim Croche" || r.Answers[1] != "7" ||
+				r.Completed.Round(time.Second).Format("2006-01-02 15:04") != "2024-03-15 10:00" {
+				t.Errorf("response = %+v", r)
+			}
+			if responses[1].Answers[1] != "" {
+				t.Errorf("short row answers = %q", responses[1].Answers)
+			}
+		})
+	}
+	if reader.Claim("responses.csv", []byte("Timestamp,")) {
+		t.Errorf("Claim() of a local file = true")
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+
+	"github.com/jjcinaz/sheeptabulator/sheep"
+)
+
+type historyTotal struct {
+	name    string
+	total   int
+	best    int
+	quizzes int
+}
+
+func (h *historyTotal) add(score int) {
+	h.total += score
+	h.quizzes++
+	if score > h.best {
+		h.best = score
+	}
+}
+
+// runHistory scores each quiz file given on the command line and totals the scores
+// across all of them
+func runHistory(fs *flag.FlagSet, args []string) int {
+	var qf quizFlags
+	fs.StringVar(&qf.teamfile, "teamfile", "", "File name of JSON file with team information (leave off if not using teams)")
+	fs.StringVar(&qf.blocklist, "blocklist", "", "File of words or phrases, one per line, which remove any answer containing them")
+	fs.StringVar(&qf.scoring, "scoring", "", "How answers score: "+sheep.ScorerNames+", for every quiz or as 3=classic for one question (default frequency)")
+	fs.StringVar(&qf.dict, "dictionary", sheep.DefaultDictionaryFile(), "JSON dictionary of synonyms kept from quiz to quiz")
+	qf.registerRead(fs)
+	missingMemberMode := missingModeFlag(fs)
+	if code := parseFlags(fs, args); code >= 0 {
+		return code
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintf(fs.Output(), "%s history: at least one response file is required\n", progName)
+		fs.Usage()
+		return exitUsage
+	}
+	missingMode, ok := parseMissingMode(fs, *missingMemberMode)
+	if !ok {
+		return exitUsage
+	}
+	opts, err := qf.readOptions()
+	if err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	canon, err := qf.canonicalizer()
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	teams, err := qf.readTeams()
+	if err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	players := make(map[string]*historyTotal)
+	teamTotals := make(map[string]*historyTotal)
+	for _, filename := range fs.Args() {
+		quiz := sheep.Quiz{Teams: teams, Canon: canon}
+		if err = quiz.ReadResponses(filename, opts); err != nil {
+			fmt.Printf("%s: %s\n", filename, err)
+			return exitError
+		}
+		quiz.EliminateDups()
+		if err = quiz.SetScoring(qf.scoring); err != nil {
+			fmt.Printf("%s: %s\n", filename, err)
+			return exitError
+		}
+		if err = setWeights(&quiz, sheep.WeightsFileName(filename)); err != nil {
+			fmt.Println(err)
+			return exitError
+		}
+		if err = moderate(&quiz, qf.blocklist, sheep.ModerationFileName(filename), ""); err != nil {
+			fmt.Println(err)
+			return exitError
+		}
+		if err = setRules(&quiz, sheep.RulesFileName(filename)); err != nil {
+			fmt.Println(err)
+			return exitError
+		}
+		if err = setDictionary(&quiz, qf.dict); err != nil {
+			fmt.Println(err)
+			return exitError
+		}
+		if _, err = applyAliases(&quiz, sheep.AliasFileName(filename)); err != nil {
+			fmt.Println(err)
+			return exitError
+		}
+		quiz.CalcScores()
+		fmt.Printf("Read %d responses from %s\n", len(quiz.Responses), filename)
+		for _, m := range quiz.PlayerScores() {
+			if players[m.Email] == nil {
+				players[m.Email] = &historyTotal{name: m.Name}
+			}
+			players[m.Email].add(m.Score)
+		}
+		if quiz.TeamMode() {
+			for _, ts := range quiz.TeamScores(missingMode) {
+				if teamTotals[ts.Name] == nil {
+					teamTotals[ts.Name] = &historyTotal{name: ts.Name}
+				}
+				teamTotals[ts.Name].add(ts.Score)
+			}
+		}
+	}
+	fmt.Printf("\nPlayer Totals over %d quizzes\n", fs.NArg())
+	printHistory(players)
+	if teams != nil {
+		fmt.Printf("\nTeam Totals over %d quizzes\n", fs.NArg())
+		printHistory(teamTotals)
+	}
+	return exitOK
+}
+
+func printHistory(totals map[string]*historyTotal) {
+	sorted := make([]*historyTotal, 0, len(totals))
+	for _, h := range totals {
+		sorted = append(sorted, h)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].total == sorted[j].total {
+			return sorted[i].name < sorted[j].name
+		}
+		return sorted[i].total > sorted[j].total
+	})
+	fmt.Printf("%6s %6s %7s\n", "total", "best", "quizzes")
+	for _, h := range sorted {
+		fmt.Printf("%6d %6d %7d\t%s\n", h.total, h.best, h.quizzes, h.name)
+	}
+}
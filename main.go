@@ -1,422 +1,538 @@
 package main
 
 import (
-	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"os"
-	"path/filepath"
-	"regexp"
 	"sort"
 	"strings"
-	"time"
+
+	"github.com/jjcinaz/sheeptabulator/google"
+	"github.com/jjcinaz/sheeptabulator/sheep"
 )
 
-type Response struct {
-	Email       string
-	Name        string
-	Team        string
-	Completed   time.Time
-	Answers     []string
-	AnswerScore []int
-	TotalScore  int
-	TotalBonus  int
-}
+const progName = "sheeptabulator"
 
-type Member struct {
-	Email string `json:"email"`
-	Name  string `json:"name"`
-	Team  string `json:"-"`
-}
+// Exit codes
+const (
+	exitOK      = 0
+	exitUsage   = 1 // bad command line
+	exitError   = 2 // could not read or score the input
+	exitInvalid = 3 // input was read but failed validation
+)
 
-type PopulationCount struct {
-	Freq           int
-	OriginalAnswer string
-	Bonus          int // If this was a bonus answer, what's the bonus value
+type command struct {
+	name    string
+	args    string // argument synopsis shown after the flags in the usage line
+	summary string
+	run     func(fs *flag.FlagSet, args []string) int
 }
 
-type Question struct {
-	Text             string
-	BonusQuestion    bool
-	BonusAnswer      string
-	BonusValue       int
-	PopulationCounts map[string]*PopulationCount
+var commands = []command{
+	{name: "score", summary: "Score a quiz and print the answers, player scores, and team scores", run: runScore},
+	{name: "report", summary: "Print the stack-ranked answers for presenting a quiz", run: runReport},
+	{name: "normalize", args: "[list|suggest|edit|rules|promote]", summary: "Help normalize answers before scoring", run: runNormalize},
+	{name: "export-normalized", summary: "Write a copy of the responses with the normalized answers filled in", run: runExportNormalized},
+	{name: "validate", summary: "Check a response file and teams file for problems", run: runValidate},
+	{name: "history", args: "file...", summary: "Total player and team scores over several quizzes", run: runHistory},
+	{name: "teams", summary: "Print the teams and members in a teams file", run: runTeams},
 }
 
-var (
-	Teams     map[string][]Member // map[team name][]Member
-	Questions []Question
-	TeamMode  bool
-)
+func init() {
+	sheep.RegisterReader(&google.SheetsReader{})
+}
 
 func main() {
-	var (
-		Responses []Response
-		err       error
-	)
-	individual := flag.Bool("i", false, "Show individual question/answer scores")
-	sortByResponse := flag.Bool("r", false, "Sort by response text instead of response frequency")
-	filename := flag.String("f", "", "Spreadsheet with responses to read")
-	teamfile := flag.String("teamfile", "", "File name of JSON file with team information (leave off if not using teams)")
-	missingMemberMode := flag.String("missing", "avg", "Mode for handling missing members: avg, least, middle")
-	printteams := flag.Bool("print", false, "Print Teams")
-	flag.Parse()
-	if *printteams {
-		if err := getTeams(*teamfile); err != nil {
-			fmt.Println(err)
-			os.Exit(2)
-		}
-		fmt.Printf("Read %d Teams and %d members from %s\n", len(Teams), totalMembers(), *teamfile)
-		if *printteams {
-			printTeams()
-			os.Exit(0)
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	if len(args) == 0 {
+		usage()
+		return exitUsage
+	}
+	name := args[0]
+	if name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		if len(args) > 1 {
+			if c := findCommand(args[1]); c != nil {
+				// Let the command register its flags, then ask it for help
+				return c.run(c.flagSet(), []string{"-h"})
+			}
 		}
+		usage()
+		return exitOK
 	}
-	if len(*filename) == 0 {
-		flag.PrintDefaults()
-		os.Exit(1)
-	}
-	switch *missingMemberMode {
-	case "avg", "least", "middle":
-	default:
-		fmt.Println("-missing must be 'avg', 'least', or 'middle'")
-		os.Exit(1)
+	c := findCommand(name)
+	if c == nil {
+		fmt.Fprintf(os.Stderr, "%s: unknown command '%s'\n", progName, name)
+		usage()
+		return exitUsage
 	}
-	if len(*teamfile) > 0 {
-		TeamMode = true
-	}
-	if TeamMode {
-		if err = getTeams(*teamfile); err != nil {
-			fmt.Println(err)
-			os.Exit(2)
+	fs := c.flagSet()
+	return c.run(fs, args[1:])
+}
+
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
 		}
-		fmt.Printf("Read %d Teams and %d members from %s\n", len(Teams), totalMembers(), *teamfile)
-	} else {
-		fmt.Printf("Teams mode is disabled\n")
 	}
-	Responses, err = readResponses(*filename)
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(2)
+	return nil
+}
+
+func (c *command) flagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "usage: %s %s [flags]", progName, c.name)
+		if len(c.args) > 0 {
+			fmt.Fprintf(out, " %s", c.args)
+		}
+		fmt.Fprintf(out, "\n\n%s\n\n", c.summary)
+		fs.PrintDefaults()
 	}
-	Responses = eliminateDups(Responses)
-	fmt.Printf("Read %d responses\n", len(Responses))
-	calcScores(Responses)
-	if TeamMode {
-		printMissingMembers(Responses)
+	return fs
+}
+
+// parseFlags parses the command's flags and returns a non-negative exit code if the
+// command should stop now
+func parseFlags(fs *flag.FlagSet, args []string) int {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
 	}
-	printScores(Responses, *individual, *missingMemberMode, *sortByResponse)
+	return -1
 }
 
-// Read responses from input file
-func readResponses(filename string) ([]Response, error) {
-	switch strings.ToUpper(filepath.Ext(filename)) {
-	case ".XLS", ".XLSX":
-		return readXLSX(filename)
-	case ".CSV":
-		return readCSV(filename)
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %s <command> [flags]\n\nCommands:\n", progName)
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.summary)
 	}
-	return nil, fmt.Errorf("invalid file type, must be .xls, .xlsx, or .csv")
+	fmt.Fprintf(os.Stderr, "\nRun '%s help <command>' for the flags of a command.\n", progName)
 }
 
-func printMissingMembers(responses []Response) {
-	for name, members := range Teams {
-		for _, member := range members {
-			found := false
-			for _, r := range responses {
-				if strings.EqualFold(r.Email, member.Email) {
-					found = true
-					break
-				}
-			}
-			if !found {
-				fmt.Printf("Missing response from %s on team %s\n", member.Email, name)
-			}
+// fileList is a flag which may be given more than once
+type fileList []string
+
+func (f *fileList) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *fileList) Set(s string) error {
+	*f = append(*f, s)
+	return nil
+}
+
+// quizFlags are the flags shared by commands which read a quiz
+type quizFlags struct {
+	filenames fileList
+	teamfile  string
+	profile   string
+	sheet     string
+	aliases   string
+	canon     string
+	phonetic  string
+	blocklist string
+	report    string
+	rules     string
+	dict      string
+	scoring   string
+	weights   string
+}
+
+func (qf *quizFlags) register(fs *flag.FlagSet) {
+	fs.Var(&qf.filenames, "f", "Spreadsheet (.xlsx, .ods, .csv, or sheets://<spreadsheetId>/<range>) with responses to read; repeat to merge the responses of several files")
+	fs.StringVar(&qf.teamfile, "teamfile", "", "File name of JSON file with team information (leave off if not using teams)")
+	fs.StringVar(&qf.aliases, "aliases", "", "JSON file mapping variant answers to canonical answers (default: <first file>.aliases.json if it exists)")
+	fs.StringVar(&qf.blocklist, "blocklist", "", "File of words or phrases, one per line, which remove any answer containing them")
+	fs.StringVar(&qf.report, "moderation-report", "", "File to list the answers removed by moderation and who gave them in (default: none, only how many is printed)")
+	fs.StringVar(&qf.rules, "rules", "", "JSON file of regular expression rules which rewrite the answers to a question (default: <first file>.rules.json if it exists)")
+	fs.StringVar(&qf.dict, "dictionary", sheep.DefaultDictionaryFile(), "JSON dictionary of synonyms kept from quiz to quiz")
+	fs.StringVar(&qf.scoring, "scoring", "", "How answers score: "+sheep.ScorerNames+", for the quiz or as 3=classic for one question; a list such as classic,3=herd:2 (default frequency)")
+	fs.StringVar(&qf.weights, "weights", "", "JSON file of question weights, such as {\"10\": 2} (default: <first file>.weights.json if it exists)")
+	fs.StringVar(&qf.phonetic, "phonetic", "", "Questions whose answers which sound alike are merged automatically: all, or question numbers such as 3,7")
+	qf.registerRead(fs)
+}
+
+// registerRead registers the flags which control how response files are read
+func (qf *quizFlags) registerRead(fs *flag.FlagSet) {
+	fs.StringVar(&qf.profile, "profile", "", "Column layout of the responses: microsoft-forms, google-forms, or a JSON profile file (default: detect)")
+	fs.StringVar(&qf.sheet, "sheet", "", "Sheet of a workbook to read (default: the first with the expected column titles, * reads every sheet as a round)")
+	fs.StringVar(&qf.canon, "canon", "all", "Normalizers which make answers the same: all, none, or a list of "+sheep.Normalizers())
+}
+
+// canonicalizer makes the answer canonicalizer given with -canon
+func (qf *quizFlags) canonicalizer() (*sheep.Canonicalizer, error) {
+	return sheep.ParseCanonicalizer(qf.canon)
+}
+
+func (qf *quizFlags) readOptions() (sheep.ReadOptions, error) {
+	var (
+		opts = sheep.ReadOptions{Sheet: qf.sheet}
+		err  error
+	)
+	if len(qf.profile) > 0 {
+		if opts.Profile, err = sheep.LoadProfile(qf.profile); err != nil {
+			return opts, err
 		}
 	}
+	return opts, nil
 }
 
-func printScores(responses []Response, individual bool, missingMemberMode string, sortByResponse bool) {
-	type memberscore struct {
-		name, email string
-		score       int
+// readTeams reads the teams file, if one was given
+func (qf *quizFlags) readTeams() (sheep.Teams, error) {
+	if len(qf.teamfile) == 0 {
+		fmt.Printf("Teams mode is disabled\n")
+		return nil, nil
 	}
+	teams, err := sheep.ReadTeams(qf.teamfile)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Read %d Teams and %d members from %s\n", len(teams), teams.TotalMembers(), qf.teamfile)
+	return teams, nil
+}
 
-	// Print Questions and stack-ranked answers
-	for i, q := range Questions {
-		fmt.Printf("Question #%d -- %s\n", i+1, q.Text)
-		// PopulationCounts is a map, so let's make it into an array so we can sort it
-		type x struct {
-			Answer   string
-			PopCount int
-		}
-		a := make([]x, 0, len(q.PopulationCounts))
-		for _, p := range q.PopulationCounts {
-			a = append(a, x{Answer: p.OriginalAnswer, PopCount: p.Freq})
-		}
-		if sortByResponse {
-			sort.Slice(a, func(i, j int) bool {
-				return a[i].Answer < a[j].Answer
-			})
-		} else {
-			sort.Slice(a, func(i, j int) bool {
-				return a[i].PopCount > a[j].PopCount
-			})
-		}
-		for i := range a {
-			if q.BonusQuestion && strings.EqualFold(q.BonusAnswer, a[i].Answer) {
-				fmt.Printf("\t%3d 🎯\t%s\n", q.BonusValue, a[i].Answer)
-			} else {
-				fmt.Printf("\t%3d\t%s\n", a[i].PopCount, a[i].Answer)
-			}
-		}
+// load reads the teams and the responses from every file and drops duplicate responses
+func (qf *quizFlags) load() (*sheep.Quiz, error) {
+	var quiz sheep.Quiz
+	opts, err := qf.readOptions()
+	if err != nil {
+		return nil, err
 	}
-	if individual {
-		// Print Individual Scores
-		for idxR := range responses {
-			fmt.Println(responses[idxR].Name)
-			for i, a := range responses[idxR].Answers {
-				fmt.Printf("\t%2d: %3d\t%s\n", i, responses[idxR].AnswerScore[i], a)
-			}
-			fmt.Printf("\t-----------------------\n\t total %d\n", responses[idxR].TotalScore)
-		}
-		fmt.Println("")
-	}
-	sortedScores := make([]memberscore, 0)
-	for _, r := range responses {
-		sortedScores = append(sortedScores, memberscore{name: r.Name, email: r.Email, score: r.TotalScore})
-	}
-	sort.Slice(sortedScores, func(i, j int) bool {
-		return sortedScores[i].score > sortedScores[j].score
-	})
-	fmt.Println("\nPlayer Scores")
-	for _, m := range sortedScores {
-		fmt.Printf("%4d\t%s\n", m.score, m.name)
-	}
-	if !TeamMode {
-		return
-	}
-
-	// Make a slice of team info with scores, so we can sort it
-	type teamscore struct {
-		name    string
-		score   int
-		members []memberscore
-	}
-	sortedTeams := make([]teamscore, 0, len(Teams))
-	for n := range Teams {
-		var ts teamscore
-		membercount := 0
-		for _, r := range responses {
-			if r.Team == n {
-				ts.score += r.TotalScore
-				membercount++
-			}
-		}
-		ts.name = n
-		ts.members = make([]memberscore, 0, 4)
-		for _, r := range responses {
-			if r.Team == n {
-				ts.members = append(ts.members, memberscore{
-					name:  r.Name,
-					email: r.Email,
-					score: r.TotalScore,
-				})
-			}
+	if quiz.Canon, err = qf.canonicalizer(); err != nil {
+		return nil, err
+	}
+	if quiz.Teams, err = qf.readTeams(); err != nil {
+		return nil, err
+	}
+	for _, filename := range qf.filenames {
+		before := len(quiz.Responses)
+		if err = quiz.AddResponses(filename, opts); err != nil {
+			return nil, err
 		}
-		sort.Slice(ts.members, func(i, j int) bool {
-			return ts.members[i].score > ts.members[j].score
-		})
-		fillinscore := 0
-		if membercount > 0 && membercount < 4 {
-			switch missingMemberMode {
-			case "avg":
-				fillinscore = ts.score / membercount
-			case "least":
-				fillinscore = ts.members[len(ts.members)-1].score
-			case "middle":
-				switch len(ts.members) {
-				case 0:
-					fillinscore = 0
-				case 1:
-					fillinscore = ts.members[0].score
-				case 2, 3:
-					fillinscore = ts.members[1].score
-				}
-			}
-			ts.score += fillinscore * (4 - membercount)
-			for ; membercount < 4; membercount++ {
-				ts.members = append(ts.members, memberscore{score: fillinscore, name: "--------"})
-			}
+		if len(qf.filenames) > 1 {
+			fmt.Printf("Read %d responses from %s\n", len(quiz.Responses)-before, filename)
 		}
-		sortedTeams = append(sortedTeams, ts)
-	}
-	sort.Slice(sortedTeams, func(i, j int) bool {
-		return sortedTeams[i].score > sortedTeams[j].score
-	})
-
-	// Print team scores
-	fmt.Println("\nTeam Scores")
-	for _, ts := range sortedTeams {
-		fmt.Printf("%4d\t%s\n", ts.score, ts.name)
-		for _, m := range ts.members {
-			fmt.Printf("\t%4d\t%s\n", m.score, m.name)
+	}
+	for _, r := range quiz.EliminateDups() {
+		if len(qf.filenames) > 1 {
+			fmt.Printf("Ignoring earlier response from %s in %s\n", r.Email, r.Source)
 		}
 	}
+	fmt.Printf("Read %d responses\n", len(quiz.Responses))
+	if err = quiz.SetScoring(qf.scoring); err != nil {
+		return nil, err
+	}
+	if err = setWeights(&quiz, qf.weightsFile()); err != nil {
+		return nil, err
+	}
+	if err = moderate(&quiz, qf.blocklist, qf.moderationFile(), qf.report); err != nil {
+		return nil, err
+	}
+	if err = setRules(&quiz, qf.rulesFile()); err != nil {
+		return nil, err
+	}
+	if err = setDictionary(&quiz, qf.dict); err != nil {
+		return nil, err
+	}
+	aliases, err := applyAliases(&quiz, qf.aliasFile())
+	if err != nil {
+		return nil, err
+	}
+	if err = qf.mergeSoundAlike(&quiz, aliases); err != nil {
+		return nil, err
+	}
+	return &quiz, nil
 }
 
-func (q *Question) mostFreqAnswer() int {
-	max := 0
-	for _, v := range q.PopulationCounts {
-		if v.Freq > max {
-			max = v.Freq
-		}
+// mergeSoundAlike merges the answers which sound alike in the questions given with
+// -phonetic, listing every merge for the judge to review
+func (qf *quizFlags) mergeSoundAlike(quiz *sheep.Quiz, aliases *sheep.Aliases) error {
+	questions, err := parseNumbers("-phonetic", qf.phonetic, len(quiz.Questions))
+	if err != nil || len(questions) == 0 {
+		return err
+	}
+	groups, err := quiz.MergeSoundAlike(aliases, questions)
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		fmt.Printf("Merged by sound in question #%d: %s into %s\n", g.Question+1, strings.Join(g.Variants(), ", "), g.Canonical())
 	}
-	return max
+	fmt.Printf("Merged %d groups of answers which sound alike\n", len(groups))
+	return nil
 }
 
-func calcScores(responses []Response) {
-	// First create a map for each question with the frequency of each answer
-	for _, r := range responses {
-		for i, answerText := range r.Answers {
-			if len(answerText) > 0 {
-				a := strings.ToLower(answerText)
-				if pc, exists := Questions[i].PopulationCounts[a]; exists {
-					pc.Freq++
-				} else {
-					Questions[i].PopulationCounts[a] = &PopulationCount{Freq: 1, OriginalAnswer: answerText}
-				}
-			}
-		}
+// aliasFile is the alias file given with -aliases, or else the one next to the first
+// response file
+func (qf *quizFlags) aliasFile() string {
+	if len(qf.aliases) > 0 || len(qf.filenames) == 0 {
+		return qf.aliases
 	}
+	return sheep.AliasFileName(qf.filenames[0])
+}
 
-	// Calculate value of bonus answers
-	for idxQ := range Questions {
-		if Questions[idxQ].BonusQuestion {
-			i := Questions[idxQ].mostFreqAnswer()
-			Questions[idxQ].BonusValue = i + (i >> 1)
-		}
+// weightsFile is the weights file given with -weights, or the one next to the first
+// response file
+func (qf *quizFlags) weightsFile() string {
+	if len(qf.weights) > 0 || len(qf.filenames) == 0 {
+		return qf.weights
 	}
+	return sheep.WeightsFileName(qf.filenames[0])
+}
 
-	// Now go through the answers in each response and assign the score to each based on the frequency map
-	for idxR := range responses {
-		responses[idxR].TotalScore = 0
-		for i, a := range responses[idxR].Answers {
-			if len(a) > 0 {
-				a = strings.ToLower(a)
-				score := Questions[i].PopulationCounts[a].Freq
-				if Questions[i].BonusQuestion && strings.EqualFold(Questions[i].BonusAnswer, a) {
-					score = Questions[i].BonusValue
-				}
-				responses[idxR].AnswerScore[i] = score
-				responses[idxR].TotalScore += score
-			}
-		}
+// setWeights sets the weights of the questions in the weights file
+func setWeights(quiz *sheep.Quiz, filename string) error {
+	if len(filename) == 0 {
+		return nil
+	}
+	weights, err := sheep.ReadWeights(filename)
+	if err != nil {
+		return err
+	}
+	if err = quiz.SetWeights(weights); err != nil {
+		return fmt.Errorf("%s: %s", filename, err)
+	}
+	return nil
+}
+
+// moderationFile is the moderation file of the quiz
+func (qf *quizFlags) moderationFile() string {
+	if len(qf.filenames) == 0 {
+		return ""
 	}
+	return sheep.ModerationFileName(qf.filenames[0])
 }
 
-func eliminateDups(responses []Response) []Response {
-	// Eliminate duplicate responses by a member, taking the last completed one only
-	sort.Slice(responses, func(i, j int) bool {
-		if responses[i].Email == responses[j].Email {
-			return responses[i].Completed.Before(responses[j].Completed)
+// moderate removes the answers containing a phrase of the blocklist file or the moderation
+// file and the answers the moderation file blanks.  Who gave them is written to the report
+// file, which only the quiz master can read, so nothing removed is printed.  With no report
+// file only the number removed is printed.
+func moderate(quiz *sheep.Quiz, blocklist, filename, report string) error {
+	m := &sheep.Moderation{}
+	if len(filename) > 0 {
+		var err error
+		if m, err = sheep.ReadModeration(filename); err != nil {
+			return err
 		}
-		return responses[i].Email < responses[j].Email
-	})
-	var last Response
-	newlist := make([]Response, 0, len(responses))
-	for i, a := range responses {
-		if i > 0 {
-			if a.Email != last.Email {
-				newlist = append(newlist, last)
-			}
+	}
+	if len(blocklist) > 0 {
+		phrases, err := sheep.ReadBlocklist(blocklist)
+		if err != nil {
+			return err
 		}
-		last = a
+		m.Blocklist = append(m.Blocklist, phrases...)
+	}
+	if m.Empty() {
+		return nil
+	}
+	removed, err := quiz.Moderate(m)
+	if err != nil {
+		return fmt.Errorf("%s: %s", filename, err)
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	if len(report) == 0 {
+		fmt.Printf("Removed %d answers by moderation\n", len(removed))
+		return nil
+	}
+	f, err := os.OpenFile(report, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
 	}
-	if len(last.Email) > 0 {
-		newlist = append(newlist, last)
+	if err = quiz.WriteModerationReport(f, removed); err != nil {
+		f.Close()
+		return err
 	}
-	return newlist
+	if err = f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Removed %d answers by moderation; see %s\n", len(removed), report)
+	return nil
 }
 
-func totalMembers() int {
-	m := 0
-	for _, v := range Teams {
-		m += len(v)
+// rulesFile is the rules file given with -rules, or the one next to the first response file
+func (qf *quizFlags) rulesFile() string {
+	if len(qf.rules) > 0 || len(qf.filenames) == 0 {
+		return qf.rules
 	}
-	return m
+	return sheep.RulesFileName(qf.filenames[0])
 }
 
-func findTeam(email string) (string, error) {
-	for _, v := range Teams {
-		for _, m := range v {
-			if m.Email == email {
-				return m.Team, nil
-			}
-		}
+// setRules gives the quiz the rules in the rules file, which run when the aliases are applied
+func setRules(quiz *sheep.Quiz, filename string) error {
+	if len(filename) == 0 {
+		return nil
+	}
+	rules, err := sheep.ReadRules(filename)
+	if err != nil {
+		return err
 	}
-	return "", fmt.Errorf("cannot find '%s' on any team", email)
+	if err = quiz.SetRules(rules); err != nil {
+		return fmt.Errorf("%s: %s", filename, err)
+	}
+	if n := rules.Count(); n > 0 {
+		fmt.Printf("Read %d rules from %s\n", n, filename)
+	}
+	return nil
 }
 
-func firstRune(s string) rune {
-	var first rune
-	for _, r := range s {
-		first = r
-		break
+// setDictionary gives the quiz the synonyms in the dictionary file, which are looked up
+// with the aliases
+func setDictionary(quiz *sheep.Quiz, filename string) error {
+	if len(filename) == 0 {
+		return nil
+	}
+	dict, err := sheep.ReadDictionary(filename)
+	if err != nil {
+		return err
 	}
-	return first
+	if n := dict.Count(); n > 0 {
+		quiz.SetDictionary(dict)
+		fmt.Printf("Read %d synonyms from %s\n", n, filename)
+	}
+	return nil
 }
 
-func buildQuestion(text string) (q Question, err error) {
-	if firstRune(text) == '🎯' {
-		// Bonus question.  Expect bonus answer to be on the end of the title: "Question [bonus answer]"
-		regx := regexp.MustCompile(`(?U)(^.+)\s*\[(.*)\]\s*$`)
-		if matches := regx.FindStringSubmatch(text); matches == nil {
-			err = fmt.Errorf("bonus question is missing ending answer <%s [answer]>", text)
-			return
-		} else {
-			q = Question{
-				Text:             matches[1],
-				BonusAnswer:      matches[2],
-				BonusQuestion:    true,
-				PopulationCounts: make(map[string]*PopulationCount),
-			}
+// applyAliases replaces variant answers with the canonical answers in the alias file or the
+// dictionary, after any rules have rewritten them, and returns the aliases read
+func applyAliases(quiz *sheep.Quiz, filename string) (*sheep.Aliases, error) {
+	aliases := &sheep.Aliases{}
+	if len(filename) > 0 {
+		var err error
+		if aliases, err = sheep.ReadAliases(filename); err != nil {
+			return nil, err
 		}
+	}
+	if len(aliases.Questions) == 0 && !quiz.HasRules() && !quiz.HasDictionary() {
+		return aliases, nil
+	}
+	n, err := quiz.ApplyAliases(aliases)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", filename, err)
+	}
+	if len(aliases.Questions) == 0 {
+		fmt.Printf("Replaced %d answers with rules and synonyms\n", n)
 	} else {
-		q = Question{Text: text, PopulationCounts: make(map[string]*PopulationCount)}
+		fmt.Printf("Replaced %d answers with aliases from %s\n", n, filename)
 	}
-	return
+	return aliases, nil
 }
 
-func getTeams(filename string) error {
-	b, err := ioutil.ReadFile(filename)
+// requireFile reports a usage error if no response file was given
+func (qf *quizFlags) requireFile(fs *flag.FlagSet) bool {
+	if len(qf.filenames) == 0 {
+		fmt.Fprintf(fs.Output(), "%s %s: -f is required\n", progName, fs.Name())
+		fs.Usage()
+		return false
+	}
+	return true
+}
+
+func missingModeFlag(fs *flag.FlagSet) *string {
+	return fs.String("missing", "avg", "Mode for handling missing members: avg, least, middle")
+}
+
+func parseMissingMode(fs *flag.FlagSet, s string) (sheep.MissingMode, bool) {
+	m, err := sheep.ParseMissingMode(s)
 	if err != nil {
-		return err
+		fmt.Fprintf(fs.Output(), "-missing must be 'avg', 'least', or 'middle'\n")
+		return "", false
 	}
-	if err = json.Unmarshal(b, &Teams); err == nil {
-		for teamName, members := range Teams {
-			for i := range members {
-				members[i].Email = strings.ToLower(members[i].Email)
-				members[i].Team = teamName
+	return m, true
+}
+
+func printMissingMembers(quiz *sheep.Quiz) {
+	for _, member := range quiz.MissingMembers() {
+		fmt.Printf("Missing response from %s on team %s\n", member.Email, member.Team)
+	}
+}
+
+// printAnswers prints the questions and their stack-ranked answers
+func printAnswers(quiz *sheep.Quiz, sortByResponse bool, only int) {
+	for i := range quiz.Questions {
+		if only > 0 && only != i+1 {
+			continue
+		}
+		q := &quiz.Questions[i]
+		scorer := quiz.QuestionScorer(i)
+		var notes []string
+		if _, ok := scorer.(sheep.FrequencyScorer); !ok {
+			notes = append(notes, scorer.Name()+" scoring")
+		}
+		if q.Weighted() {
+			notes = append(notes, fmt.Sprintf("worth ×%g", q.Weight))
+		}
+		if len(notes) > 0 {
+			fmt.Printf("Question #%d -- %s (%s)\n", i+1, q.Text, strings.Join(notes, ", "))
+		} else {
+			fmt.Printf("Question #%d -- %s\n", i+1, q.Text)
+		}
+		for _, p := range q.SortedCounts(sortByResponse) {
+			score := scorer.Score(p.Freq, q, len(quiz.Responses))
+			if quiz.IsBonusAnswer(i, p.OriginalAnswer) && q.Bonus.Additive {
+				fmt.Printf("\t%3d 🎯\t%s (%d + %d bonus)\n", score+q.BonusValue, p.OriginalAnswer, score, q.BonusValue)
+			} else if quiz.IsBonusAnswer(i, p.OriginalAnswer) {
+				fmt.Printf("\t%3d 🎯\t%s\n", q.BonusValue, p.OriginalAnswer)
+			} else if score != p.Freq {
+				fmt.Printf("\t%3d\t%s (given by %d)\n", score, p.OriginalAnswer, p.Freq)
+			} else {
+				fmt.Printf("\t%3d\t%s\n", p.Freq, p.OriginalAnswer)
 			}
 		}
 	}
-	return err
 }
 
-func printTeams() {
-	for name, members := range Teams {
+func printTeams(teams sheep.Teams) {
+	names := teams.Names()
+	for _, name := range names {
 		fmt.Printf("Team Name: %s\n", name)
-		for _, member := range members {
+		for _, member := range teams[name] {
 			fmt.Printf("\t%s\n", member.Name)
 		}
 	}
 
-	for _, members := range Teams {
-		for _, member := range members {
+	for _, name := range names {
+		for _, member := range teams[name] {
 			fmt.Printf("%s,", member.Email)
 		}
 	}
 	fmt.Println("")
 }
+
+func runTeams(fs *flag.FlagSet, args []string) int {
+	teamfile := fs.String("teamfile", "", "File name of JSON file with team information")
+	if code := parseFlags(fs, args); code >= 0 {
+		return code
+	}
+	if len(*teamfile) == 0 {
+		fmt.Fprintf(fs.Output(), "%s teams: -teamfile is required\n", progName)
+		fs.Usage()
+		return exitUsage
+	}
+	teams, err := sheep.ReadTeams(*teamfile)
+	if err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	fmt.Printf("Read %d Teams and %d members from %s\n", len(teams), teams.TotalMembers(), *teamfile)
+	printTeams(teams)
+	return exitOK
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
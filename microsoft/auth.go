@@ -0,0 +1,107 @@
+// Package microsoft reads quiz responses from Microsoft 365 services.
+package microsoft
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/microsoft"
+)
+
+// credentialsFile holds the application (client) id registered in Microsoft Entra ID and
+// the tenant to sign in to, e.g. {"clientId": "...", "tenant": "acme.com"}.  The app must
+// allow public client flows, since the device code login has no client secret.
+const credentialsFile = "ms-credentials.json"
+
+type credentials struct {
+	ClientID string `json:"clientId"`
+	Tenant   string `json:"tenant"`
+}
+
+// endpoint is the Microsoft identity platform endpoint of the tenant, with the device code
+// URL the oauth2 package leaves out
+func endpoint(tenant string) oauth2.Endpoint {
+	if tenant == "" {
+		tenant = "common"
+	}
+	e := microsoft.AzureADEndpoint(tenant)
+	e.DeviceAuthURL = "https://login.microsoftonline.com/" + tenant + "/oauth2/v2.0/devicecode"
+	return e
+}
+
+// clientFromCredentials returns an OAuth client for the scopes using the application in
+// ms-credentials.json.  The token is cached in tokFile.
+func clientFromCredentials(ctx context.Context, tokFile string, scopes ...string) (*http.Client, error) {
+	b, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %s", credentialsFile, err)
+	}
+	var c credentials
+	if err = json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %s", credentialsFile, err)
+	}
+	if len(c.ClientID) == 0 {
+		return nil, fmt.Errorf("%s has no clientId", credentialsFile)
+	}
+	config := &oauth2.Config{
+		ClientID: c.ClientID,
+		Endpoint: endpoint(c.Tenant),
+		Scopes:   append(scopes, "offline_access"),
+	}
+	return getClient(ctx, config, tokFile)
+}
+
+// getClient returns a client using the token cached in tokFile, signing in with a device
+// code the first time.  The cached token holds the refresh token, so later runs do not have
+// to sign in again.
+func getClient(ctx context.Context, config *oauth2.Config, tokFile string) (*http.Client, error) {
+	tok, err := tokenFromFile(tokFile)
+	if err != nil {
+		if tok, err = getTokenFromDevice(ctx, config); err != nil {
+			return nil, err
+		}
+		if err = saveToken(tokFile, tok); err != nil {
+			return nil, err
+		}
+	}
+	return config.Client(ctx, tok), nil
+}
+
+// getTokenFromDevice asks the user to sign in on another device and waits for the token
+func getTokenFromDevice(ctx context.Context, config *oauth2.Config) (*oauth2.Token, error) {
+	da, err := config.DeviceAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to start device login: %s", err)
+	}
+	fmt.Printf("To sign in, go to %s and enter the code %s\n", da.VerificationURI, da.UserCode)
+	tok, err := config.DeviceAccessToken(ctx, da)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve token: %s", err)
+	}
+	return tok, nil
+}
+
+func tokenFromFile(file string) (*oauth2.Token, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	tok := &oauth2.Token{}
+	err = json.NewDecoder(f).Decode(tok)
+	return tok, err
+}
+
+func saveToken(path string, token *oauth2.Token) error {
+	fmt.Printf("Saving credential file to: %s\n", path)
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to cache oauth token: %s", err)
+	}
+	defer f.Close()
+	return json.NewEncoder(f).Encode(token)
+}
@@ -0,0 +1,192 @@
+package microsoft
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/jjcinaz/sheeptabulator/sheep"
+)
+
+// FormsScheme starts the name of an input read from Microsoft Forms: msforms://<formId>.
+// The form id is the id= parameter of the form's link.
+const FormsScheme = "msforms://"
+
+// DefaultFormsURL is the Forms API.  Microsoft Graph has no endpoint for form responses, so
+// they are fetched from the API Forms itself uses, signing in to the same Microsoft
+// identity platform as Graph.  The API is unofficial and undocumented: the shapes read
+// here are assumed from what the Forms site sends, and Microsoft may change them at any
+// time.  Downloading the XLSX file is the supported way to get the responses.
+const DefaultFormsURL = "https://forms.office.com/formapi/api"
+
+// formsScope is the delegated permission to read the signed in user's forms
+const formsScope = "https://forms.office.com/.default"
+
+// FormsReader reads the responses of a Microsoft Form without downloading them as an
+// XLSX file.  It is experimental, since the API it reads is unofficial, and the tabulator
+// registers it with sheep.RegisterReader only when built with -tags msforms.
+type FormsReader struct {
+	// BaseURL of the Forms API, DefaultFormsURL when empty
+	BaseURL string
+	// Client makes the requests.  When nil, the user signs in with a device code using the
+	// application in ms-credentials.json, and the token is saved in token-msforms.json.
+	Client *http.Client
+}
+
+type formJSON struct {
+	Title     string         `json:"title"`
+	Questions []questionJSON `json:"questions"`
+}
+
+type questionJSON struct {
+	ID    string  `json:"id"`
+	Title string  `json:"title"`
+	Order float64 `json:"order"`
+}
+
+type responsesJSON struct {
+	Value    []responseJSON `json:"value"`
+	NextLink string         `json:"@odata.nextLink"`
+}
+
+type responseJSON struct {
+	ID            int64           `json:"id"`
+	StartDate     string          `json:"startDate"`
+	SubmitDate    string          `json:"submitDate"`
+	Responder     string          `json:"responder"`
+	ResponderName string          `json:"responderName"`
+	Answers       json.RawMessage `json:"answers"`
+}
+
+type answerJSON struct {
+	QuestionID string `json:"questionId"`
+	Answer     string `json:"answer1"`
+}
+
+func (*FormsReader) Name() string { return "Microsoft Forms API" }
+
+func (*FormsReader) Claim(filename string, head []byte) bool {
+	return strings.HasPrefix(filename, FormsScheme)
+}
+
+// Read gets the questions and responses of the form and lays them out like the XLSX file
+// Forms exports, so they are checked and parsed the same way as a downloaded file.
+func (r *FormsReader) Read(filename string, opts sheep.ReadOptions) ([]sheep.Question, []sheep.Response, error) {
+	id := strings.Trim(strings.TrimPrefix(filename, FormsScheme), "/")
+	if len(id) == 0 {
+		return nil, nil, fmt.Errorf("%s has no form id, expect %s<formId>", filename, FormsScheme)
+	}
+	ctx := context.Background()
+	client := r.Client
+	if client == nil {
+		var err error
+		if client, err = clientFromCredentials(ctx, "token-msforms.json", formsScope); err != nil {
+			return nil, nil, err
+		}
+	}
+	base := r.BaseURL
+	if len(base) == 0 {
+		base = DefaultFormsURL
+	}
+	formURL := strings.TrimRight(base, "/") + "/forms('" + url.PathEscape(id) + "')"
+
+	var form formJSON
+	if err := getJSON(ctx, client, formURL+"?$expand=questions", &form); err != nil {
+		return nil, nil, fmt.Errorf("unable to get form %s: %s", id, err)
+	}
+	rows, err := formRows(&form)
+	if err != nil {
+		return nil, nil, err
+	}
+	for next := formURL + "/responses"; len(next) > 0; {
+		var page responsesJSON
+		if err = getJSON(ctx, client, next, &page); err != nil {
+			return nil, nil, fmt.Errorf("unable to get responses of form %s: %s", id, err)
+		}
+		for _, resp := range page.Value {
+			row, err := responseRow(&form, &resp)
+			if err != nil {
+				return nil, nil, err
+			}
+			rows = append(rows, row)
+		}
+		next = page.NextLink
+	}
+	return sheep.ParseRows(rows, opts)
+}
+
+// formRows sorts the questions into the order they appear on the form and returns the
+// header row of the Forms export
+func formRows(form *formJSON) ([][]string, error) {
+	if len(form.Questions) == 0 {
+		return nil, fmt.Errorf("form '%s' has no questions", form.Title)
+	}
+	sort.SliceStable(form.Questions, func(i, j int) bool {
+		return form.Questions[i].Order < form.Questions[j].Order
+	})
+	header := []string{"ID", "Start time", "Completion time", "Email", "Name"}
+	for _, q := range form.Questions {
+		header = append(header, q.Title)
+	}
+	return [][]string{header}, nil
+}
+
+func responseRow(form *formJSON, resp *responseJSON) ([]string, error) {
+	answers, err := parseAnswers(resp.Answers)
+	if err != nil {
+		return nil, fmt.Errorf("response %d from %s: %s", resp.ID, resp.Responder, err)
+	}
+	row := []string{fmt.Sprint(resp.ID), resp.StartDate, resp.SubmitDate, resp.Responder, resp.ResponderName}
+	for _, q := range form.Questions {
+		row = append(row, answers[q.ID])
+	}
+	return row, nil
+}
+
+// parseAnswers maps question ids to answers.  The API returns the answers as a JSON array
+// encoded in a string.
+func parseAnswers(raw json.RawMessage) (map[string]string, error) {
+	answers := make(map[string]string)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return answers, nil
+	}
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+		raw = json.RawMessage(s)
+	}
+	var list []answerJSON
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, fmt.Errorf("unable to parse answers: %s", err)
+	}
+	for _, a := range list {
+		answers[a.QuestionID] = a.Answer
+	}
+	return answers, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, u string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(b))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
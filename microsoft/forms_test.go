@@ -0,0 +1,141 @@
+package microsoft
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jjcinaz/sheeptabulator/sheep"
+	"golang.org/x/oauth2"
+)
+
+// newFormsStub stands in for the Forms API with one form, "quiz1", whose responses come back
+// in two pages
+func newFormsStub(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	var srv *httptest.Server
+	mux.HandleFunc("/formapi/api/forms('quiz1')", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("$expand") != "questions" {
+			t.Errorf("$expand = %s", r.URL.Query().Get("$expand"))
+		}
+		fmt.Fprint(w, `{"title":"Sheep quiz","questions":[
+			{"id":"r2","title":"🎯 A number [7]","order":2000},
+			{"id":"r1","title":"A sweetener","order":1000}]}`)
+	})
+	mux.HandleFunc("/formapi/api/forms('quiz1')/responses", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `{"value":[{"id":2,"submitDate":"2024-03-15T10:05:00Z","responder":"bhf@acme.com",
+				"responderName":"Bill Fettman","answers":[{"questionId":"r1","answer1":"Honey"}]}]}`)
+			return
+		}
+		answers, _ := json.Marshal(`[{"questionId":"r1","answer1":"Sugar"},{"questionId":"r2","answer1":"7"}]`)
+		fmt.Fprintf(w, `{"value":[{"id":1,"startDate":"2024-03-15T09:58:00Z","submitDate":"2024-03-15T10:00:00Z",
+			"responder":"JJC@acme.com","responderName":"Jim Croche","answers":%s}],
+			"@odata.nextLink":"%s/formapi/api/forms('quiz1')/responses?page=2"}`, answers, srv.URL)
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFormsReader(t *testing.T) {
+	srv := newFormsStub(t)
+	reader := &FormsReader{BaseURL: srv.URL + "/formapi/api", Client: srv.Client()}
+	tests := []struct {
+		name     string
+		filename string
+		wantErr  string
+	}{
+		{"form", "msforms://quiz1", ""},
+		{"unknown form", "msforms://quiz2", "404"},
+		{"no id", "msforms://", "no form id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reader.Claim(tt.filename, nil) {
+				t.Fatalf("Claim(%s) = false", tt.filename)
+			}
+			questions, responses, err := reader.Read(tt.filename, sheep.ReadOptions{})
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Read() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if len(questions) != 2 || questions[0].Text != "A sweetener" ||
+				!questions[1].BonusQuestion || strings.Join(questions[1].BonusAnswers, "|") != "7" {
+				t.Errorf("questions = %+v", questions)
+			}
+			if len(responses) != 2 {
+				t.Fatalf("responses = %+v", responses)
+			}
+			r := responses[0]
+			if r.Email != "jjc@acme.com" || r.Name != "Jim Croche" || r.Answers[0] != "Sugar" || r.Answers[1] != "7" ||
+				r.Completed.Format("2006-01-02 15:04") != "2024-03-15 10:00" {
+				t.Errorf("response = %+v", r)
+			}
+			if responses[1].Answers[0] != "Honey" || responses[1].Answers[1] != "" {
+				t.Errorf("second page answers = %q", responses[1].Answers)
+			}
+		})
+	}
+	if reader.Claim("responses.xlsx", nil) {
+		t.Errorf("Claim() of a local file = true")
+	}
+}
+
+func Test_getClient(t *testing.T) {
+	var logins int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/devicecode", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&logins, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"device_code":"dc","user_code":"ABCD","verification_uri":"https://microsoft.com/devicelogin","interval":1,"expires_in":60}`)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("device_code") != "dc" {
+			t.Errorf("device_code = %s", r.Form.Get("device_code"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"at","refresh_token":"rt","token_type":"Bearer","expires_in":3600}`)
+	})
+	mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer at" {
+			t.Errorf("Authorization = %s", got)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	config := &oauth2.Config{
+		ClientID: "app",
+		Endpoint: oauth2.Endpoint{DeviceAuthURL: srv.URL + "/devicecode", TokenURL: srv.URL + "/token"},
+		Scopes:   []string{formsScope},
+	}
+	tokFile := filepath.Join(t.TempDir(), "token.json")
+	for i := 0; i < 2; i++ {
+		client, err := getClient(context.Background(), config, tokFile)
+		if err != nil {
+			t.Fatalf("getClient() error = %v", err)
+		}
+		if _, err = client.Get(srv.URL + "/me"); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+	if logins != 1 {
+		t.Errorf("device logins = %d, want 1 with the token cached", logins)
+	}
+	if tok, err := tokenFromFile(tokFile); err != nil || tok.RefreshToken != "rt" {
+		t.Errorf("cached token = %+v, %v", tok, err)
+	}
+}
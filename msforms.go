@@ -0,0 +1,14 @@
+//go:build msforms
+
+package main
+
+import (
+	"github.com/jjcinaz/sheeptabulator/microsoft"
+	"github.com/jjcinaz/sheeptabulator/sheep"
+)
+
+// The Microsoft Forms reader reads an unofficial API which has not been checked against the
+// live service, so it is only built in with -tags msforms
+func init() {
+	sheep.RegisterReader(&microsoft.FormsReader{})
+}
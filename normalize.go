@@ -0,0 +1,328 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jjcinaz/sheeptabulator/sheep"
+)
+
+func runNormalize(fs *flag.FlagSet, args []string) int {
+	action := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+	}
+	switch action {
+	case "list":
+		return runNormalizeList(fs, args)
+	case "suggest":
+		return runNormalizeSuggest(fs, args)
+	case "edit":
+		return runNormalizeEdit(fs, args)
+	case "rules":
+		return runNormalizeRules(fs, args)
+	case "promote":
+		return runNormalizePromote(fs, args)
+	}
+	fmt.Fprintf(fs.Output(), "%s normalize: unknown action '%s'\n", progName, action)
+	fs.Usage()
+	return exitUsage
+}
+
+// runNormalizeList prints every distinct answer to each question in alphabetical order, so
+// answers which need to be made the same sit next to each other
+func runNormalizeList(fs *flag.FlagSet, args []string) int {
+	var qf quizFlags
+	qf.register(fs)
+	question := fs.Int("q", 0, "Only show this question number")
+	if code := parseFlags(fs, args); code >= 0 {
+		return code
+	}
+	if !qf.requireFile(fs) {
+		return exitUsage
+	}
+	quiz, err := qf.load()
+	if err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	if *question < 0 || *question > len(quiz.Questions) {
+		fmt.Printf("-q must be between 1 and %d\n", len(quiz.Questions))
+		return exitUsage
+	}
+	quiz.CalcScores()
+	for i := range quiz.Questions {
+		if *question > 0 && *question != i+1 {
+			continue
+		}
+		fmt.Printf("Question #%d -- %s\n", i+1, quiz.Questions[i].Text)
+		variants := answerVariants(quiz, i)
+		for _, p := range quiz.Questions[i].SortedCounts(true) {
+			fmt.Printf("\t%3d\t%s\n", p.Freq, p.OriginalAnswer)
+			if v := variants[quiz.AnswerKey(i, p.OriginalAnswer)]; len(v) > 1 {
+				fmt.Printf("\t\t(%s)\n", strings.Join(v, " | "))
+			}
+		}
+	}
+	return exitOK
+}
+
+// runNormalizeSuggest prints groups of answers which look like the same answer, and saves
+// the groups accepted with -accept to the alias file
+func runNormalizeSuggest(fs *flag.FlagSet, args []string) int {
+	var qf quizFlags
+	qf.register(fs)
+	question := fs.Int("q", 0, "Only suggest merges for this question number")
+	threshold := fs.Float64("threshold", sheep.DefaultMergeThreshold, "Similarity from 0 to 1 at which answers are suggested as the same")
+	accept := fs.String("accept", "", "Groups to save to the alias file: all, or a list of group numbers such as 1,3,4")
+	sounds := fs.Bool("sounds", false, "Also suggest answers which sound alike, such as names spelled differently")
+	if code := parseFlags(fs, args); code >= 0 {
+		return code
+	}
+	if !qf.requireFile(fs) {
+		return exitUsage
+	}
+	quiz, err := qf.load()
+	if err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	if *question < 0 || *question > len(quiz.Questions) {
+		fmt.Printf("-q must be between 1 and %d\n", len(quiz.Questions))
+		return exitUsage
+	}
+	quiz.CalcScores()
+	var groups []sheep.MergeGroup
+	for _, g := range quiz.SuggestMerges(sheep.SuggestOptions{Threshold: *threshold, Phonetic: *sounds}) {
+		if *question == 0 || *question == g.Question+1 {
+			groups = append(groups, g)
+		}
+	}
+	accepted, err := parseNumbers("-accept", *accept, len(groups))
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	last := -1
+	for i, g := range groups {
+		if g.Question != last {
+			fmt.Printf("Question #%d -- %s\n", g.Question+1, quiz.Questions[g.Question].Text)
+			last = g.Question
+		}
+		variants := make([]string, 0, len(g.Answers)-1)
+		for _, p := range g.Answers[1:] {
+			variants = append(variants, fmt.Sprintf("%s (%d)", p.OriginalAnswer, p.Freq))
+		}
+		sound := ""
+		if g.Phonetic {
+			sound = "  (sounds alike)"
+		}
+		fmt.Printf("\t%3d. %3.0f%%\t%s (%d) <- %s%s\n", i+1, g.Confidence*100, g.Canonical(), g.Answers[0].Freq, strings.Join(variants, ", "), sound)
+	}
+	if len(groups) == 0 {
+		fmt.Printf("No answers are at least %.0f%% similar\n", *threshold*100)
+	}
+	if len(accepted) == 0 {
+		return exitOK
+	}
+	filename := qf.aliasFile()
+	if len(filename) == 0 {
+		fmt.Printf("-aliases is required to save aliases for %s\n", qf.filenames[0])
+		return exitUsage
+	}
+	aliases, err := sheep.ReadAliases(filename)
+	if err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	n := 0
+	for _, i := range accepted {
+		g := groups[i]
+		for _, v := range g.Variants() {
+			aliases.Add(aliases.QuestionKey(quiz, g.Question), v, g.Canonical())
+			n++
+		}
+	}
+	if err = aliases.Save(filename); err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	fmt.Printf("Saved %d aliases to %s\n", n, filename)
+	return exitOK
+}
+
+// runNormalizeRules is a dry run of the rules file, showing what each rule changes in the
+// answers as read
+func runNormalizeRules(fs *flag.FlagSet, args []string) int {
+	var qf quizFlags
+	qf.register(fs)
+	question := fs.Int("q", 0, "Only show the rules for this question number")
+	if code := parseFlags(fs, args); code >= 0 {
+		return code
+	}
+	if !qf.requireFile(fs) {
+		return exitUsage
+	}
+	quiz, err := qf.load()
+	if err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	if *question < 0 || *question > len(quiz.Questions) {
+		fmt.Printf("-q must be between 1 and %d\n", len(quiz.Questions))
+		return exitUsage
+	}
+	last, shown := -1, 0
+	for _, e := range quiz.RuleEffects() {
+		if *question > 0 && *question != e.Question+1 {
+			continue
+		}
+		if e.Question != last {
+			fmt.Printf("Question #%d -- %s\n", e.Question+1, quiz.Questions[e.Question].Text)
+			last = e.Question
+		}
+		fmt.Printf("\t%s -> %q changes %d answers\n", e.Rule.Find, e.Rule.Replace, len(e.Changes))
+		for _, c := range e.Changes {
+			after := c.After
+			if len(after) == 0 {
+				after = "(blank)"
+			}
+			fmt.Printf("\t\t%3d\t%s -> %s\n", c.Count, c.Before, after)
+		}
+		shown++
+	}
+	if shown == 0 {
+		fmt.Println("No rules to show")
+	}
+	return exitOK
+}
+
+// runNormalizePromote adds the answers merged in this quiz, by aliases, rules, or sound, to
+// the dictionary so they are merged in later quizzes too
+func runNormalizePromote(fs *flag.FlagSet, args []string) int {
+	var qf quizFlags
+	qf.register(fs)
+	question := fs.Int("q", 0, "Only promote the merges of this question number")
+	topic := fs.String("topic", "", "Topic to add the synonyms to (default: the question's topics in the alias file)")
+	global := fs.Bool("global", false, "Add the synonyms of questions with no topic for every question of every quiz")
+	if code := parseFlags(fs, args); code >= 0 {
+		return code
+	}
+	if !qf.requireFile(fs) {
+		return exitUsage
+	}
+	if len(qf.dict) == 0 {
+		fmt.Println("-dictionary is required to promote merges")
+		return exitUsage
+	}
+	if *question == 0 && len(*topic) == 0 && !*global {
+		fmt.Println("-q, -topic, or -global is required to promote merges")
+		return exitUsage
+	}
+	// Merges by sound are not reviewed, so they are never promoted
+	qf.phonetic = ""
+	quiz, err := qf.load()
+	if err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	if *question < 0 || *question > len(quiz.Questions) {
+		fmt.Printf("-q must be between 1 and %d\n", len(quiz.Questions))
+		return exitUsage
+	}
+	aliases := &sheep.Aliases{}
+	if filename := qf.aliasFile(); len(filename) > 0 {
+		if aliases, err = sheep.ReadAliases(filename); err != nil {
+			fmt.Println(err)
+			return exitError
+		}
+	}
+	dict, err := sheep.ReadDictionary(qf.dict)
+	if err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	quiz.CalcScores()
+	n := 0
+	for i := range quiz.Questions {
+		if *question > 0 && *question != i+1 {
+			continue
+		}
+		topics := []string{*topic}
+		if len(*topic) == 0 {
+			if topics = aliases.QuestionTopics(quiz, i); len(topics) == 0 {
+				if !*global {
+					fmt.Printf("Question #%d has no topic, give -topic or -global to promote its merges\n", i+1)
+					continue
+				}
+				topics = []string{""}
+			}
+		}
+		merged := quiz.MergedAnswers(i)
+		for _, answer := range sortedKeys(merged) {
+			for _, t := range topics {
+				if added := dict.Add(t, answer, merged[answer]...); added > 0 {
+					fmt.Printf("Question #%d: %s <- %s\n", i+1, answer, strings.Join(merged[answer], ", "))
+					n += added
+				}
+			}
+		}
+	}
+	if n == 0 {
+		fmt.Printf("No new synonyms for %s\n", qf.dict)
+		return exitOK
+	}
+	if err = dict.Save(qf.dict); err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	fmt.Printf("Promoted %d synonyms to %s\n", n, qf.dict)
+	return exitOK
+}
+
+// parseNumbers turns a flag's list of numbers from 1 to max, or all, into indexes
+func parseNumbers(name, s string, max int) ([]int, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	var indexes []int
+	if s == "all" {
+		for i := 0; i < max; i++ {
+			indexes = append(indexes, i)
+		}
+		return indexes, nil
+	}
+	for _, f := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil || n < 1 || n > max {
+			return nil, fmt.Errorf("%s must be all or numbers between 1 and %d, not '%s'", name, max, f)
+		}
+		indexes = append(indexes, n-1)
+	}
+	return indexes, nil
+}
+
+// answerVariants returns the distinct spellings of each answer to a question, keyed the
+// same way the answers are grouped when scoring
+func answerVariants(quiz *sheep.Quiz, idxQ int) map[string][]string {
+	seen := make(map[string]map[string]bool)
+	for _, r := range quiz.Responses {
+		if idxQ >= len(r.Answers) {
+			continue
+		}
+		for _, a := range quiz.AnswerEntries(idxQ, r.Answers[idxQ]) {
+			key := quiz.AnswerKey(idxQ, a)
+			if seen[key] == nil {
+				seen[key] = make(map[string]bool)
+			}
+			seen[key][a] = true
+		}
+	}
+	variants := make(map[string][]string, len(seen))
+	for key, spellings := range seen {
+		variants[key] = sortedKeys(spellings)
+	}
+	return variants
+}
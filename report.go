@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+// runReport prints only the answers, so they can be presented before the scores are revealed
+func runReport(fs *flag.FlagSet, args []string) int {
+	var qf quizFlags
+	qf.register(fs)
+	sortByResponse := fs.Bool("r", false, "Sort by response text instead of response frequency")
+	question := fs.Int("q", 0, "Only show this question number")
+	if code := parseFlags(fs, args); code >= 0 {
+		return code
+	}
+	if !qf.requireFile(fs) {
+		return exitUsage
+	}
+	quiz, err := qf.load()
+	if err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	if *question < 0 || *question > len(quiz.Questions) {
+		fmt.Printf("-q must be between 1 and %d\n", len(quiz.Questions))
+		return exitUsage
+	}
+	quiz.CalcScores()
+	printAnswers(quiz, *sortByResponse, *question)
+	return exitOK
+}
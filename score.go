@@ -0,0 +1,150 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jjcinaz/sheeptabulator/sheep"
+)
+
+func runScore(fs *flag.FlagSet, args []string) int {
+	var qf quizFlags
+	qf.register(fs)
+	individual := fs.Bool("i", false, "Show individual question/answer scores")
+	sortByResponse := fs.Bool("r", false, "Sort by response text instead of response frequency")
+	missingMemberMode := missingModeFlag(fs)
+	output := fs.String("o", "", "Save the scored quiz to this file in the canonical quiz format (.json or .jsonl)")
+	pinkCow := fs.String("pinkcow", "", "Play with the pink cow, keeping who holds it between quizzes in this JSON file")
+	penalty := fs.Int("pinkcow-penalty", sheep.DefaultPinkCowPenalty, "Points the player holding the pink cow at the end of the quiz loses")
+	quizName := fs.String("quiz", "", "Name of the quiz in the pink cow file (default: the full paths of the response files)")
+	if code := parseFlags(fs, args); code >= 0 {
+		return code
+	}
+	if !qf.requireFile(fs) {
+		return exitUsage
+	}
+	missingMode, ok := parseMissingMode(fs, *missingMemberMode)
+	if !ok {
+		return exitUsage
+	}
+	quiz, err := qf.load()
+	if err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	var cowState *sheep.PinkCowState
+	if len(*pinkCow) > 0 {
+		if cowState, err = sheep.ReadPinkCowState(*pinkCow); err != nil {
+			fmt.Println(err)
+			return exitError
+		}
+		quiz.PinkCow = cowState.Game(pinkCowQuiz(*quizName, qf.filenames), *penalty)
+	}
+	quiz.CalcScores()
+	if quiz.TeamMode() {
+		printMissingMembers(quiz)
+	}
+	printScores(quiz, *individual, missingMode, *sortByResponse, len(qf.filenames) > 1)
+	if cowState != nil {
+		cowState.Record(quiz.PinkCow)
+		if err = cowState.Save(*pinkCow); err != nil {
+			fmt.Println(err)
+			return exitError
+		}
+	}
+	if len(*output) > 0 {
+		if err = quiz.Save(*output); err != nil {
+			fmt.Println(err)
+			return exitError
+		}
+		fmt.Printf("Saved quiz to %s\n", *output)
+	}
+	return exitOK
+}
+
+func printScores(quiz *sheep.Quiz, individual bool, missingMemberMode sheep.MissingMode, sortByResponse bool, showSource bool) {
+	printAnswers(quiz, sortByResponse, 0)
+	if individual {
+		// Print Individual Scores
+		for _, r := range quiz.Responses {
+			if showSource {
+				fmt.Printf("%s (from %s)\n", r.Name, r.Source)
+			} else {
+				fmt.Println(r.Name)
+			}
+			for i, a := range r.Answers {
+				if q := &quiz.Questions[i]; q.Weighted() {
+					fmt.Printf("\t%2d: %3d\t%s (%d ×%g)\n", i, r.AnswerScore[i], a, r.RawScore[i], q.Weight)
+				} else {
+					fmt.Printf("\t%2d: %3d\t%s\n", i, r.AnswerScore[i], a)
+				}
+			}
+			if cow := quiz.PinkCow; cow != nil && cow.After.Email == r.Email {
+				fmt.Printf("\t    %3d\tpink cow\n", -cow.Penalty)
+			}
+			if r.TotalBonus > 0 {
+				fmt.Printf("\t-----------------------\n\t total %d, including %d bonus\n", r.TotalScore, r.TotalBonus)
+			} else {
+				fmt.Printf("\t-----------------------\n\t total %d\n", r.TotalScore)
+			}
+		}
+		fmt.Println("")
+	}
+	fmt.Println("\nPlayer Scores")
+	for _, m := range quiz.PlayerScores() {
+		if quiz.PinkCow != nil && quiz.PinkCow.After.Email == m.Email {
+			fmt.Printf("%4d\t%s 🐮\n", m.Score, m.Name)
+		} else {
+			fmt.Printf("%4d\t%s\n", m.Score, m.Name)
+		}
+	}
+	if quiz.PinkCow != nil {
+		printPinkCow(quiz.PinkCow)
+	}
+	if !quiz.TeamMode() {
+		return
+	}
+
+	// Print team scores
+	fmt.Println("\nTeam Scores")
+	for _, ts := range quiz.TeamScores(missingMemberMode) {
+		fmt.Printf("%4d\t%s\n", ts.Score, ts.Name)
+		for _, m := range ts.Members {
+			fmt.Printf("\t%4d\t%s\n", m.Score, m.Name)
+		}
+	}
+}
+
+// pinkCowQuiz names a quiz in the pink cow file, so scoring it again is known however its
+// response files are named on the command line
+func pinkCowQuiz(name string, filenames []string) string {
+	if len(name) > 0 {
+		return name
+	}
+	paths := make([]string, 0, len(filenames))
+	for _, filename := range filenames {
+		if !strings.Contains(filename, "://") {
+			if abs, err := filepath.Abs(filename); err == nil {
+				filename = abs
+			}
+		}
+		paths = append(paths, filename)
+	}
+	sort.Strings(paths)
+	return strings.Join(paths, ",")
+}
+
+// printPinkCow shows who holds the pink cow and how they earned it
+func printPinkCow(g *sheep.PinkCowGame) {
+	switch c := g.After; {
+	case !c.Held():
+		fmt.Println("\nNobody has the pink cow")
+	case c == g.Before && c.Quiz != g.Quiz:
+		fmt.Printf("\n🐮 %s still has the pink cow, earned in %s on question #%d with '%s'\n", c.Name, c.Quiz, c.Question, c.Answer)
+	default:
+		fmt.Printf("\n🐮 %s has the pink cow, earned on question #%d with '%s'\n", c.Name, c.Question, c.Answer)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPinkCowQuiz(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	abs := filepath.Join(wd, "quiz.xlsx")
+	tests := []struct {
+		name      string
+		filenames []string
+		want      string
+	}{
+		{"", []string{"quiz.xlsx"}, abs},
+		{"", []string{"./quiz.xlsx"}, abs},
+		{"", []string{"dir/../quiz.xlsx"}, abs},
+		{"", []string{abs}, abs},
+		{"", []string{"sheets://abc/Form1", "quiz.xlsx"}, abs + ",sheets://abc/Form1"},
+		{"week2", []string{"quiz.xlsx"}, "week2"},
+	}
+	for _, tt := range tests {
+		if got := pinkCowQuiz(tt.name, tt.filenames); got != tt.want {
+			t.Errorf("pinkCowQuiz(%q, %q) = %s, want %s", tt.name, tt.filenames, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package sheep
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Aliases maps variant answers to a canonical answer for each question of a quiz.  The
+// questions are keyed by number, starting at 1, or by their text.  Variants match the way
+// answers are grouped, and a variant mapped to "" is blanked.  Flagged answers are kept
+// for the quiz master to review, and topics tag the questions whose answers the synonyms
+// of those topics in the dictionary apply to.
+//
+//	{"questions": {"1": {"Sweet-n-Low": "Saccharin", "Sacirine": "Saccharin"}},
+//	 "topics": {"1": ["sweeteners"]}}
+type Aliases struct {
+	Questions map[string]map[string]string `json:"questions"`
+	Flagged   map[string][]string          `json:"flagged,omitempty"`
+	Topics    map[string][]string          `json:"topics,omitempty"`
+}
+
+// sidecarFile is the name of a file kept next to a response file, named after it with a
+// suffix in place of its extension.  Inputs which are not local files have none.
+func sidecarFile(filename, suffix string) string {
+	if strings.Contains(filename, "://") {
+		return ""
+	}
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + suffix
+}
+
+// readJSONFile reads a JSON file into v.  A file which does not exist leaves v as it is.
+func readJSONFile[T any](filename string, v *T) error {
+	b, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%s: %s", filename, err)
+	}
+	return nil
+}
+
+// AliasFileName is the alias file kept next to a response file: responses.xlsx has
+// responses.aliases.json.
+func AliasFileName(filename string) string {
+	return sidecarFile(filename, ".aliases.json")
+}
+
+// ReadAliases reads an alias file.  A file which does not exist has no aliases.
+func ReadAliases(filename string) (*Aliases, error) {
+	a := &Aliases{Questions: make(map[string]map[string]string)}
+	if err := readJSONFile(filename, a); err != nil {
+		return nil, err
+	}
+	if a.Questions == nil {
+		a.Questions = make(map[string]map[string]string)
+	}
+	return a, nil
+}
+
+// Save writes the alias file, one variant per line so a change is a one line edit
+func (a *Aliases) Save(filename string) error {
+	b, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(b, '\n'), 0644)
+}
+
+// Add maps a variant answer of a question to the canonical answer.  Variants which already
+// map to the variant are changed to map to the canonical answer, so an answer is only ever
+// aliased once.
+func (a *Aliases) Add(question, variant, canonical string) {
+	if a.Questions == nil {
+		a.Questions = make(map[string]map[string]string)
+	}
+	m := a.Questions[question]
+	if m == nil {
+		m = make(map[string]string)
+		a.Questions[question] = m
+	}
+	for v, c := range m {
+		if strings.EqualFold(v, canonical) {
+			canonical = c
+		}
+	}
+	for v, c := range m {
+		if strings.EqualFold(v, variant) {
+			delete(m, v)
+		} else if strings.EqualFold(c, variant) {
+			m[v] = canonical
+		}
+	}
+	if variant != canonical {
+		m[variant] = canonical
+	}
+}
+
+// Split removes the aliases of a question which map to the canonical answer, so the
+// variants are their own answers again.  The variants are returned.
+func (a *Aliases) Split(question, canonical string) []string {
+	var variants []string
+	for v, c := range a.Questions[question] {
+		if strings.EqualFold(c, canonical) {
+			variants = append(variants, v)
+			delete(a.Questions[question], v)
+		}
+	}
+	if len(a.Questions[question]) == 0 {
+		delete(a.Questions, question)
+	}
+	sort.Strings(variants)
+	return variants
+}
+
+// ToggleFlag flags an answer to a question for review, or clears the flag, and reports
+// whether it is now flagged
+func (a *Aliases) ToggleFlag(question, answer string) bool {
+	if a.Flagged == nil {
+		a.Flagged = make(map[string][]string)
+	}
+	flagged := a.Flagged[question]
+	for i, f := range flagged {
+		if strings.EqualFold(f, answer) {
+			a.Flagged[question] = append(flagged[:i:i], flagged[i+1:]...)
+			if len(a.Flagged[question]) == 0 {
+				delete(a.Flagged, question)
+			}
+			return false
+		}
+	}
+	a.Flagged[question] = append(flagged, answer)
+	return true
+}
+
+// IsFlagged reports whether an answer to a question is flagged for review
+func (a *Aliases) IsFlagged(question, answer string) bool {
+	for _, f := range a.Flagged[question] {
+		if strings.EqualFold(f, answer) {
+			return true
+		}
+	}
+	return false
+}
+
+// Clone returns a copy of the aliases which can be changed without changing these
+func (a *Aliases) Clone() *Aliases {
+	c := &Aliases{Questions: make(map[string]map[string]string, len(a.Questions))}
+	for key, variants := range a.Questions {
+		c.Questions[key] = make(map[string]string, len(variants))
+		for v, canonical := range variants {
+			c.Questions[key][v] = canonical
+		}
+	}
+	if a.Flagged != nil {
+		c.Flagged = make(map[string][]string, len(a.Flagged))
+		for key, flagged := range a.Flagged {
+			c.Flagged[key] = append([]string(nil), flagged...)
+		}
+	}
+	if a.Topics != nil {
+		c.Topics = make(map[string][]string, len(a.Topics))
+		for key, topics := range a.Topics {
+			c.Topics[key] = append([]string(nil), topics...)
+		}
+	}
+	return c
+}
+
+// QuestionTopics returns the topics a question of the quiz is tagged with
+func (a *Aliases) QuestionTopics(q *Quiz, idxQ int) []string {
+	keys := make([]string, 0, len(a.Topics))
+	for key := range a.Topics {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var topics []string
+	for _, key := range keys {
+		if i, err := q.FindQuestion(key); err == nil && i == idxQ {
+			topics = append(topics, a.Topics[key]...)
+		}
+	}
+	return topics
+}
+
+// QuestionKey returns the key the aliases already use for a question of the quiz, or the
+// question's number if there are no aliases for it
+func (a *Aliases) QuestionKey(q *Quiz, idxQ int) string {
+	keys := make([]string, 0, len(a.Questions)+len(a.Flagged))
+	for key := range a.Questions {
+		keys = append(keys, key)
+	}
+	for key := range a.Flagged {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if i, err := q.FindQuestion(key); err == nil && i == idxQ {
+			return key
+		}
+	}
+	return strconv.Itoa(idxQ + 1)
+}
+
+// FindQuestion returns the index of the question with a number, starting at 1, or text,
+// with or without the markers at the start of its title
+func (q *Quiz) FindQuestion(key string) (int, error) {
+	if n, err := strconv.Atoi(key); err == nil {
+		if n < 1 || n > len(q.Questions) {
+			return -1, fmt.Errorf("there is no question #%d, the quiz has %d questions", n, len(q.Questions))
+		}
+		return n - 1, nil
+	}
+	key = strings.TrimSpace(trimNumberPrefix(key))
+	for i := range q.Questions {
+		text := strings.TrimSpace(trimNumberPrefix(q.Questions[i].Text))
+		if strings.EqualFold(text, key) || strings.EqualFold(trimMarkers(text), key) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("there is no question '%s'", key)
+}
+
+// ApplyAliases replaces the variant answers of every response with the canonical answer
+// and returns the number of answers changed.  The answers as read are kept in RawAnswers,
+// and aliases are always applied to them, so applying an edited alias file again undoes
+// any aliases which were removed.  Any rules given with SetRules rewrite the answers as
+// read before they are looked up, and the synonyms of any dictionary given with
+// SetDictionary are looked up with the aliases.
+func (q *Quiz) ApplyAliases(a *Aliases) (int, error) {
+	canonical := make([]map[string]string, len(q.Questions))
+	if q.dictionary != nil {
+		topics := make([][]string, len(q.Questions))
+		for key, tags := range a.Topics {
+			idxQ, err := q.FindQuestion(key)
+			if err != nil {
+				return 0, fmt.Errorf("topics: %s", err)
+			}
+			topics[idxQ] = append(topics[idxQ], tags...)
+		}
+		for idxQ := range canonical {
+			canonical[idxQ] = make(map[string]string)
+			q.synonyms(canonical[idxQ], idxQ, topics[idxQ])
+		}
+	}
+	for key, variants := range a.Questions {
+		idxQ, err := q.FindQuestion(key)
+		if err != nil {
+			return 0, fmt.Errorf("aliases: %s", err)
+		}
+		if canonical[idxQ] == nil {
+			canonical[idxQ] = make(map[string]string)
+		}
+		for variant, answer := range variants {
+			canonical[idxQ][q.AnswerKey(idxQ, variant)] = strings.TrimSpace(answer)
+		}
+	}
+	changed := 0
+	for idxR := range q.Responses {
+		r := &q.Responses[idxR]
+		if r.RawAnswers == nil {
+			r.RawAnswers = append([]string(nil), r.Answers...)
+		}
+		for i, raw := range r.RawAnswers {
+			answer := raw
+			if raws, answers := q.normalizeEntries(canonical, i, raw); !slices.Equal(raws, answers) {
+				// Blanked entries of a multi-answer question are dropped
+				answer = strings.Join(slices.DeleteFunc(answers, func(a string) bool { return len(a) == 0 }), ", ")
+			}
+			if answer != raw {
+				changed++
+			}
+			r.Answers[i] = answer
+		}
+	}
+	q.canonical = canonical
+	return changed, nil
+}
+
+// NormalizedEntries pairs each entry of an answer to a question as read with the answer it
+// became when ApplyAliases last ran, or "" if it was blanked.  An answer to a question which
+// is not a multi-answer question is a single entry.
+func (q *Quiz) NormalizedEntries(idxQ int, raw string) (raws, answers []string) {
+	if q.canonical == nil {
+		return q.entries(idxQ, raw), q.entries(idxQ, raw)
+	}
+	return q.normalizeEntries(q.canonical, idxQ, raw)
+}
+
+// entries splits an answer to a multi-answer question into its entries; any other answer
+// is a single entry
+func (q *Quiz) entries(idxQ int, answer string) []string {
+	if idxQ < len(q.Questions) && q.Questions[idxQ].Multi {
+		return splitEntries(answer)
+	}
+	return []string{answer}
+}
+
+// normalizeEntries rewrites and aliases each entry of an answer on its own
+func (q *Quiz) normalizeEntries(canonical []map[string]string, idxQ int, raw string) (raws, answers []string) {
+	raws = q.entries(idxQ, raw)
+	answers = make([]string, len(raws))
+	for i, e := range raws {
+		answers[i] = q.normalize(canonical, idxQ, e)
+	}
+	return raws, answers
+}
+
+// normalize rewrites an answer to a question with the rules and replaces it with its alias
+func (q *Quiz) normalize(canonical []map[string]string, idxQ int, answer string) string {
+	answer = q.rewrite(idxQ, answer)
+	if idxQ < len(canonical) && canonical[idxQ] != nil {
+		if c, ok := canonical[idxQ][q.AnswerKey(idxQ, answer)]; ok {
+			answer = c
+		}
+	}
+	return answer
+}
@@ -0,0 +1,130 @@
+package sheep
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestQuiz_ApplyAliases(t *testing.T) {
+	q := newTestQuiz([]string{"1. A sweetener", "A color"},
+		[]string{"Sweet-n-Low", "Red"},
+		[]string{"sacirine", "red"},
+		[]string{"Saccharin", ""},
+		[]string{"Sugar", "Blue"},
+	)
+	a := &Aliases{}
+	a.Add("1", "Sweet-n-Low", "Saccharin")
+	a.Add("a sweetener", "Sacirine", "Saccharin")
+	a.Questions["A color"] = map[string]string{"RED": "Red"}
+	n, err := q.ApplyAliases(a)
+	if err != nil {
+		t.Fatalf("ApplyAliases() error = %v", err)
+	}
+	if n != 3 {
+		t.Errorf("ApplyAliases() changed %d answers, want 3", n)
+	}
+	q.CalcScores()
+	if got := q.Questions[0].PopulationCounts["saccharin"].Freq; got != 3 {
+		t.Errorf("Saccharin Freq = %d, want 3", got)
+	}
+	if got := q.Responses[1].RawAnswers; !reflect.DeepEqual(got, []string{"sacirine", "red"}) {
+		t.Errorf("RawAnswers = %q", got)
+	}
+
+	// Removing an alias and applying the file again restores the answer as read
+	delete(a.Questions["a sweetener"], "Sacirine")
+	if _, err = q.ApplyAliases(a); err != nil {
+		t.Fatal(err)
+	}
+	if got := q.Responses[1].Answers[0]; got != "sacirine" {
+		t.Errorf("answer after removing alias = %s, want sacirine", got)
+	}
+
+	a.Add("9", "x", "y")
+	if _, err = q.ApplyAliases(a); err == nil || !strings.Contains(err.Error(), "no question #9") {
+		t.Errorf("ApplyAliases() error = %v", err)
+	}
+}
+
+func TestReadAliases(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "quiz.aliases.json")
+	a, err := ReadAliases(filename)
+	if err != nil || len(a.Questions) != 0 {
+		t.Fatalf("ReadAliases() of a missing file = %+v, %v", a, err)
+	}
+	a.Add("1", "Sweet-n-Low", "Saccharin")
+	if err = a.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadAliases(filename)
+	if err != nil || got.Questions["1"]["Sweet-n-Low"] != "Saccharin" {
+		t.Errorf("ReadAliases() = %+v, %v", got, err)
+	}
+	if name := AliasFileName("quizzes/2024-03-15.xlsx"); name != "quizzes/2024-03-15.aliases.json" {
+		t.Errorf("AliasFileName() = %s", name)
+	}
+}
+
+func Test_readJSONFile(t *testing.T) {
+	weights := map[string]float64{"1": 2}
+	if err := readJSONFile(filepath.Join(t.TempDir(), "missing.json"), &weights); err != nil || weights["1"] != 2 {
+		t.Errorf("readJSONFile() of a missing file = %v, %v", weights, err)
+	}
+	filename := writeTestFile(t, "bad.json", `{"1": "two"}`)
+	if err := readJSONFile(filename, &weights); err == nil || !strings.HasPrefix(err.Error(), filename+": ") {
+		t.Errorf("readJSONFile() of a bad file error = %v", err)
+	}
+}
+
+func TestAliases_Add(t *testing.T) {
+	a := &Aliases{}
+	a.Add("1", "Sacirine", "Saccharin")
+	a.Add("1", "Saccharin", "Saccharine")
+	a.Add("1", "Sweet-n-Low", "saccharin")
+	a.Add("1", "saccharine", "SACCHARINE")
+	want := map[string]string{"Sacirine": "SACCHARINE", "Saccharin": "SACCHARINE", "Sweet-n-Low": "SACCHARINE", "saccharine": "SACCHARINE"}
+	if !reflect.DeepEqual(a.Questions["1"], want) {
+		t.Errorf("aliases = %v, want %v", a.Questions["1"], want)
+	}
+}
+
+func TestAliases_edits(t *testing.T) {
+	q := newTestQuiz([]string{"A sweetener", "A color"},
+		[]string{"Stevia", "Red"},
+		[]string{"Steevia", "Red"},
+		[]string{"Sugar", "Blue"},
+	)
+	a := &Aliases{Questions: map[string]map[string]string{"a sweetener": {}}}
+	key := a.QuestionKey(q, 0)
+	if key != "a sweetener" || a.QuestionKey(q, 1) != "2" {
+		t.Errorf("QuestionKey() = %s, %s", key, a.QuestionKey(q, 1))
+	}
+	a.Add(key, "Steevia", "Stevia")
+	a.Add(key, "Sugar", "")
+	before := a.Clone()
+	if !a.ToggleFlag(key, "Stevia") || !a.IsFlagged(key, "stevia") {
+		t.Errorf("answer is not flagged")
+	}
+	if _, err := q.ApplyAliases(a); err != nil {
+		t.Fatal(err)
+	}
+	q.CalcScores()
+	if got := q.Questions[0].PopulationCounts["stevia"].Freq; got != 2 || q.Responses[2].TotalScore != 1 {
+		t.Errorf("Stevia Freq = %d, blanked player TotalScore = %d", got, q.Responses[2].TotalScore)
+	}
+	if variants := a.Split(key, "stevia"); !reflect.DeepEqual(variants, []string{"Steevia"}) {
+		t.Errorf("Split() = %q", variants)
+	}
+	if a.ToggleFlag(key, "STEVIA") || len(a.Flagged) != 0 {
+		t.Errorf("flag was not cleared: %v", a.Flagged)
+	}
+	if before.Questions[key]["Steevia"] != "Stevia" || len(before.Flagged) != 0 {
+		t.Errorf("Clone() shares maps: %+v", before)
+	}
+	a.Split(key, "")
+	if len(a.Questions) != 0 {
+		t.Errorf("aliases after splitting everything = %v", a.Questions)
+	}
+}
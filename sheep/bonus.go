@@ -0,0 +1,85 @@
+package sheep
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultBonusMultiplier makes the bonus 150% of the count of the most frequent answer
+const DefaultBonusMultiplier = 1.5
+
+// BonusRule is how much a bonus answer is worth and how it scores.  The zero value is the
+// default: 150% of the most frequent answer's count, in place of the answer's score.
+type BonusRule struct {
+	Multiplier float64 // bonus as a multiple of the most frequent answer's count; 0 is DefaultBonusMultiplier
+	Points     int     // a fixed bonus instead of a multiple
+	Additive   bool    // the bonus is added to the answer's score instead of replacing it
+}
+
+// value returns the bonus for a question whose most frequent answer was given by most players
+func (b BonusRule) value(most int) int {
+	if b.Points > 0 {
+		return b.Points
+	}
+	m := b.Multiplier
+	if m == 0 {
+		m = DefaultBonusMultiplier
+	}
+	return int(m * float64(most))
+}
+
+// parseBonusSpec parses the end of a bonus question's title, the accepted answers separated
+// by | and then any options separated by ;
+//
+//	Answer A|Answer B; x2.0; additive
+//
+// The options are xN for a multiple of the most frequent answer's count, a number of points,
+// and additive or replace.
+func parseBonusSpec(spec string) ([]string, BonusRule, error) {
+	var (
+		rule    BonusRule
+		answers []string
+	)
+	parts := strings.Split(spec, ";")
+	for _, a := range strings.Split(parts[0], "|") {
+		answers = append(answers, strings.TrimSpace(a))
+	}
+	for _, opt := range parts[1:] {
+		opt = strings.ToLower(strings.TrimSpace(opt))
+		switch {
+		case opt == "additive":
+			rule.Additive = true
+		case opt == "replace":
+			rule.Additive = false
+		case strings.HasPrefix(opt, "x") || strings.HasPrefix(opt, "×"):
+			m, err := strconv.ParseFloat(strings.TrimLeft(opt, "x×"), 64)
+			if err != nil || m <= 0 {
+				return nil, rule, fmt.Errorf("bonus multiplier '%s' must be a positive number", opt)
+			}
+			rule.Multiplier = m
+		default:
+			n, err := strconv.Atoi(opt)
+			if err != nil || n < 1 {
+				return nil, rule, fmt.Errorf("bonus option '%s' must be xN, a number of points, additive, or replace", opt)
+			}
+			rule.Points = n
+		}
+	}
+	return answers, rule, nil
+}
+
+// BonusSpec returns the bonus answers and any options which are not the default, as they
+// are written at the end of the question's title
+func (q *Question) BonusSpec() string {
+	spec := strings.Join(q.BonusAnswers, "|")
+	if q.Bonus.Points > 0 {
+		spec += fmt.Sprintf("; %d", q.Bonus.Points)
+	} else if q.Bonus.Multiplier > 0 && q.Bonus.Multiplier != DefaultBonusMultiplier {
+		spec += "; x" + strconv.FormatFloat(q.Bonus.Multiplier, 'f', -1, 64)
+	}
+	if q.Bonus.Additive {
+		spec += "; additive"
+	}
+	return spec
+}
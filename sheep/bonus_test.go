@@ -0,0 +1,97 @@
+package sheep
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_parseBonusSpec(t *testing.T) {
+	tests := []struct {
+		spec        string
+		wantAnswers []string
+		wantRule    BonusRule
+		wantErr     bool
+	}{
+		{"Kiwi", []string{"Kiwi"}, BonusRule{}, false},
+		{"Answer A|Answer B; x2.0; additive", []string{"Answer A", "Answer B"}, BonusRule{Multiplier: 2, Additive: true}, false},
+		{"Kiwi; 10", []string{"Kiwi"}, BonusRule{Points: 10}, false},
+		{"Kiwi ; ×3 ; Replace", []string{"Kiwi"}, BonusRule{Multiplier: 3}, false},
+		{"Kiwi; x0", nil, BonusRule{}, true},
+		{"Kiwi; double", nil, BonusRule{}, true},
+	}
+	for _, tt := range tests {
+		answers, rule, err := parseBonusSpec(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseBonusSpec(%s) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (!reflect.DeepEqual(answers, tt.wantAnswers) || rule != tt.wantRule) {
+			t.Errorf("parseBonusSpec(%s) = %q, %+v, want %q, %+v", tt.spec, answers, rule, tt.wantAnswers, tt.wantRule)
+		}
+	}
+	if _, err := buildQuestion("🎯 A fruit [Kiwi; lots]"); err == nil {
+		t.Errorf("buildQuestion() with a bad bonus option did not fail")
+	}
+}
+
+func TestQuiz_CalcScores_bonus(t *testing.T) {
+	// Apple is the most frequent answer with 4; Kiwi and Fig are given by one player each
+	answers := [][]string{{"Apple"}, {"Apple"}, {"Apple"}, {"Apple"}, {"Kiwi"}, {"Fig"}, {"Pear"}}
+	tests := []struct {
+		title     string
+		wantValue int
+		wantKiwi  int // score of the player who answered Kiwi
+		wantFig   int
+		wantBonus int // TotalBonus of the player who answered Kiwi
+	}{
+		{"🎯 A fruit [Kiwi]", 6, 6, 1, 6},
+		{"🎯 A fruit [Kiwi|Fig]", 6, 6, 6, 6},
+		{"🎯 A fruit [Kiwi|Fig; x2.0; additive]", 8, 9, 9, 8},
+		{"🎯 A fruit [kiwi; 10]", 10, 10, 1, 10},
+		{"🎯 A fruit [Apple; additive]", 6, 1, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			q := newTestQuiz([]string{tt.title}, answers...)
+			q.CalcScores()
+			if got := q.Questions[0].BonusValue; got != tt.wantValue {
+				t.Errorf("BonusValue = %d, want %d", got, tt.wantValue)
+			}
+			kiwi, fig := q.Responses[4], q.Responses[5]
+			if kiwi.TotalScore != tt.wantKiwi || fig.TotalScore != tt.wantFig || kiwi.TotalBonus != tt.wantBonus {
+				t.Errorf("Kiwi = %d (bonus %d), Fig = %d, want %d (bonus %d), %d", kiwi.TotalScore, kiwi.TotalBonus, fig.TotalScore, tt.wantKiwi, tt.wantBonus, tt.wantFig)
+			}
+		})
+	}
+	q := newTestQuiz([]string{"🎯 A fruit [Apple; additive]"}, answers...)
+	q.CalcScores()
+	if apple := q.Responses[0]; apple.TotalScore != 4+6 || apple.TotalBonus != 6 {
+		t.Errorf("additive Apple = %d (bonus %d), want 10 (bonus 6)", apple.TotalScore, apple.TotalBonus)
+	}
+}
+
+func TestQuestion_BonusSpec(t *testing.T) {
+	for _, spec := range []string{"Kiwi", "Kiwi|Fig; x2; additive", "Kiwi; 10"} {
+		q, err := buildQuestion("🎯 A fruit [" + spec + "]")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := q.BonusSpec(); got != spec {
+			t.Errorf("BonusSpec() = %s, want %s", got, spec)
+		}
+	}
+	q := newTestQuiz([]string{"🎯 A fruit [Kiwi|Fig; 10; additive]"}, []string{"Fig"})
+	q.CalcScores()
+	filename := filepath.Join(t.TempDir(), "quiz.json")
+	if err := q.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+	var got Quiz
+	if err := got.ReadResponses(filename, ReadOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got.Questions[0].BonusSpec() != "Kiwi|Fig; 10; additive" {
+		t.Errorf("bonus read = %s", got.Questions[0].BonusSpec())
+	}
+}
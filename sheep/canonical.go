@@ -0,0 +1,148 @@
+package sheep
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalizer is one step in turning an answer into the key it is grouped and scored by
+type Normalizer string
+
+// The normalizers always run in this order, whichever of them are used
+const (
+	NormNFKC        Normalizer = "nfkc"        // Unicode compatibility forms, e.g. non-breaking spaces and ligatures
+	NormDiacritics  Normalizer = "diacritics"  // drop accents, "Beyoncé" as "beyonce"
+	NormAmpersand   Normalizer = "ampersand"   // "&" as "and"
+	NormPunctuation Normalizer = "punctuation" // drop apostrophes, other punctuation as spaces
+	NormWhitespace  Normalizer = "whitespace"  // runs of spaces as one space
+	NormArticles    Normalizer = "articles"    // drop a leading "the", "a", or "an"
+)
+
+var allNormalizers = []Normalizer{NormNFKC, NormDiacritics, NormAmpersand, NormPunctuation, NormWhitespace, NormArticles}
+
+// Canonicalizer makes the key of an answer.  Answers are always compared regardless of
+// case and leading or trailing spaces; the normalizers it is made with go further.  The
+// zero value only ignores case and spaces.
+type Canonicalizer struct {
+	use map[Normalizer]bool
+}
+
+// DefaultCanonicalizer uses every normalizer
+var DefaultCanonicalizer = NewCanonicalizer(allNormalizers...)
+
+func NewCanonicalizer(normalizers ...Normalizer) *Canonicalizer {
+	c := &Canonicalizer{use: make(map[Normalizer]bool, len(normalizers))}
+	for _, n := range normalizers {
+		c.use[n] = true
+	}
+	return c
+}
+
+// ParseCanonicalizer makes a canonicalizer from a comma separated list of normalizers, or
+// "all" or "none"
+func ParseCanonicalizer(s string) (*Canonicalizer, error) {
+	switch strings.TrimSpace(s) {
+	case "all":
+		return DefaultCanonicalizer, nil
+	case "none", "":
+		return NewCanonicalizer(), nil
+	}
+	var normalizers []Normalizer
+	for _, f := range strings.Split(s, ",") {
+		n := Normalizer(strings.TrimSpace(f))
+		known := false
+		for _, k := range allNormalizers {
+			known = known || n == k
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown normalizer '%s', expect all, none, or a list of: %s", n, Normalizers())
+		}
+		normalizers = append(normalizers, n)
+	}
+	return NewCanonicalizer(normalizers...), nil
+}
+
+// Normalizers lists the names of the normalizers in the order they run
+func Normalizers() string {
+	names := make([]string, 0, len(allNormalizers))
+	for _, n := range allNormalizers {
+		names = append(names, string(n))
+	}
+	return strings.Join(names, ", ")
+}
+
+var (
+	articles        = []string{"the ", "a ", "an "}
+	foldDiacritics  = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	apostropheRunes = "'’‘`´"
+)
+
+// Key returns the key of an answer.  Answers with the same key are the same answer.  An
+// answer which normalizes to nothing, such as "?!", keeps its own key.
+func (c *Canonicalizer) Key(answer string) string {
+	original := answer
+	if c.use[NormNFKC] {
+		answer = norm.NFKC.String(answer)
+	}
+	answer = strings.ToLower(answer)
+	if c.use[NormDiacritics] {
+		if folded, _, err := transform.String(foldDiacritics, answer); err == nil {
+			answer = folded
+		}
+	}
+	if c.use[NormAmpersand] {
+		answer = strings.ReplaceAll(answer, "&", " and ")
+	}
+	if c.use[NormPunctuation] {
+		answer = strings.Map(func(r rune) rune {
+			switch {
+			case strings.ContainsRune(apostropheRunes, r):
+				return -1
+			case unicode.IsPunct(r):
+				return ' '
+			}
+			return r
+		}, answer)
+	}
+	if c.use[NormWhitespace] {
+		answer = strings.Join(strings.Fields(answer), " ")
+	}
+	answer = strings.TrimSpace(answer)
+	if c.use[NormArticles] {
+		for _, a := range articles {
+			if rest := strings.TrimPrefix(answer, a); rest != answer && len(strings.TrimSpace(rest)) > 0 {
+				answer = strings.TrimSpace(rest)
+				break
+			}
+		}
+	}
+	if len(answer) == 0 {
+		return strings.ToLower(strings.TrimSpace(original))
+	}
+	return answer
+}
+
+// AnswerKey returns the key an answer to a question is grouped and scored by.  Answers to
+// numeric questions which read as a number are keyed by the number; other answers use the
+// quiz's canonicalizer or the default one.
+func (q *Quiz) AnswerKey(idxQ int, answer string) string {
+	if idxQ < len(q.Questions) && q.Questions[idxQ].Numeric {
+		if key, ok := numericKey(answer); ok {
+			return key
+		}
+	}
+	return q.canon().Key(answer)
+}
+
+// canon returns the quiz's canonicalizer, or the default one if it has none
+func (q *Quiz) canon() *Canonicalizer {
+	if q.Canon != nil {
+		return q.Canon
+	}
+	return DefaultCanonicalizer
+}
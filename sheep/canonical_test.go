@@ -0,0 +1,79 @@
+package sheep
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCanonicalizer_Key(t *testing.T) {
+	tests := []struct {
+		name   string
+		canon  *Canonicalizer
+		answer string
+		want   string
+	}{
+		{"case and spaces", NewCanonicalizer(), "  The Beatles ", "the beatles"},
+		{"article", DefaultCanonicalizer, "The Beatles", "beatles"},
+		{"punctuation", DefaultCanonicalizer, "beatles!", "beatles"},
+		{"non-breaking space", DefaultCanonicalizer, "Beatles ", "beatles"},
+		{"non-breaking space inside", DefaultCanonicalizer, "The Beatles", "beatles"},
+		{"ligature", DefaultCanonicalizer, "ﬁsh", "fish"},
+		{"diacritics", DefaultCanonicalizer, "Beyoncé", "beyonce"},
+		{"ampersand", DefaultCanonicalizer, "Salt & Pepper", "salt and pepper"},
+		{"ampersand no spaces", DefaultCanonicalizer, "Salt&Pepper", "salt and pepper"},
+		{"hyphens", DefaultCanonicalizer, "Sweet-n-Low", "sweet n low"},
+		{"apostrophe", DefaultCanonicalizer, "Rock’n’Roll", "rocknroll"},
+		{"only an article", DefaultCanonicalizer, "A", "a"},
+		{"article in a word", DefaultCanonicalizer, "Theater", "theater"},
+		{"all punctuation", DefaultCanonicalizer, " ?! ", "?!"},
+		{"articles only", NewCanonicalizer(NormArticles), "A  Beatle", "beatle"},
+		{"diacritics only", NewCanonicalizer(NormDiacritics), "Crème brûlée!", "creme brulee!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.canon.Key(tt.answer); got != tt.want {
+				t.Errorf("Key(%q) = %q, want %q", tt.answer, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCanonicalizer(t *testing.T) {
+	c, err := ParseCanonicalizer("articles, punctuation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Key("The Beatles!"); got != "beatles" {
+		t.Errorf("Key() = %q", got)
+	}
+	if got := c.Key("Beyoncé"); got != "beyoncé" {
+		t.Errorf("Key() = %q", got)
+	}
+	if c, err = ParseCanonicalizer("none"); err != nil || c.Key("The Beatles!") != "the beatles!" {
+		t.Errorf("ParseCanonicalizer(none) = %v", err)
+	}
+	if _, err = ParseCanonicalizer("articles,soundex"); err == nil || !strings.Contains(err.Error(), "'soundex'") {
+		t.Errorf("ParseCanonicalizer() error = %v", err)
+	}
+}
+
+func TestQuiz_CalcScores_canonical(t *testing.T) {
+	q := newTestQuiz([]string{"A band", "🎯 A singer [Beyonce]"},
+		[]string{"The Beatles", "Beyoncé"},
+		[]string{"Beatles", "Adele"},
+		[]string{"beatles!", "Adele"},
+		[]string{"Beatles ", "beyonce!"},
+	)
+	q.CalcScores()
+	if len(q.Questions[0].PopulationCounts) != 1 || q.Questions[0].PopulationCounts["beatles"].OriginalAnswer != "The Beatles" {
+		t.Errorf("PopulationCounts = %v", q.Questions[0].PopulationCounts)
+	}
+	if q.Responses[0].TotalScore != 4+3 || q.Responses[3].AnswerScore[1] != 3 || !q.IsBonusAnswer(1, "BEYONCÉ") {
+		t.Errorf("scores = %d, %v", q.Responses[0].TotalScore, q.Responses[3].AnswerScore)
+	}
+	q.Canon = NewCanonicalizer()
+	q.CalcScores()
+	if len(q.Questions[0].PopulationCounts) != 3 || q.Responses[0].AnswerScore[1] != 1 {
+		t.Errorf("without normalizers PopulationCounts = %v, scores = %v", q.Questions[0].PopulationCounts, q.Responses[0].AnswerScore)
+	}
+}
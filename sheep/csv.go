@@ -0,0 +1,62 @@
+package sheep
+
+import (
+	"encoding/csv"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Read responses from CSV file exported from Google Forms or Google Sheets, or any other
+// CSV the profile describes.  Expect row 1 to have column titles in it with rows 2+
+// having the data
+func readCSV(filename string, opts ReadOptions) ([]Question, []Response, error) {
+	rows, err := csvRows(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ParseRows(rows, opts)
+}
+
+func csvRows(filename string) ([][]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		rows[0][0] = strings.TrimPrefix(rows[0][0], "\ufeff")
+	}
+	return rows, nil
+}
+
+// csvHeader reads just the first row of a CSV file
+func csvHeader(filename string) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	row, err := csv.NewReader(f).Read()
+	if err != nil {
+		return nil, err
+	}
+	row[0] = strings.TrimPrefix(row[0], "\ufeff")
+	return row, nil
+}
+
+// trimNumberPrefix removes a number prefix from a string
+// This function does not work with unicode space characters
+func trimNumberPrefix(s string) string {
+	var regex = regexp.MustCompile(`^\s*\d+\.\s*`)
+	if loc := regex.FindStringIndex(s); loc != nil {
+		return s[loc[1]:]
+	}
+	return s
+}
@@ -0,0 +1,176 @@
+package sheep
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Dictionary keeps the synonyms of answers from quiz to quiz, keyed by the canonical
+// answer.  Synonyms apply to every question, and the synonyms of a topic only to the
+// questions tagged with it in their alias file.  An alias of the quiz wins over a topic's
+// synonym, which wins over a synonym for every question.
+//
+//	{"synonyms": {"Saccharin": ["Sweet-n-Low", "Sacirine"]},
+//	 "topics": {"presidents": {"Abraham Lincoln": ["Honest Abe", "Lincoln"]}}}
+type Dictionary struct {
+	Synonyms map[string][]string            `json:"synonyms,omitempty"`
+	Topics   map[string]map[string][]string `json:"topics,omitempty"`
+}
+
+// DefaultDictionaryFile is the dictionary used unless another is named: dictionary.json
+// in the user's configuration directory.  It is "" if there is no such directory.
+func DefaultDictionaryFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "sheeptabulator", "dictionary.json")
+}
+
+// ReadDictionary reads a dictionary file.  A file which does not exist has no synonyms.
+func ReadDictionary(filename string) (*Dictionary, error) {
+	d := &Dictionary{}
+	if err := readJSONFile(filename, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Save writes the dictionary file, making its directory if needed
+func (d *Dictionary) Save(filename string) error {
+	b, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(b, '\n'), 0644)
+}
+
+// Count returns the number of synonyms in the dictionary
+func (d *Dictionary) Count() int {
+	n := 0
+	for _, variants := range d.Synonyms {
+		n += len(variants)
+	}
+	for _, synonyms := range d.Topics {
+		for _, variants := range synonyms {
+			n += len(variants)
+		}
+	}
+	return n
+}
+
+// scope returns the synonyms of a topic, or for every question if topic is ""
+func (d *Dictionary) scope(topic string) map[string][]string {
+	if len(topic) == 0 {
+		if d.Synonyms == nil {
+			d.Synonyms = make(map[string][]string)
+		}
+		return d.Synonyms
+	}
+	if d.Topics == nil {
+		d.Topics = make(map[string]map[string][]string)
+	}
+	if d.Topics[topic] == nil {
+		d.Topics[topic] = make(map[string][]string)
+	}
+	return d.Topics[topic]
+}
+
+// Add adds variants of a canonical answer to a topic, or for every question if topic is "",
+// and returns the number added.  A variant which is already a synonym in the topic, or
+// which is the same answer as the canonical one, is skipped.
+func (d *Dictionary) Add(topic, canonical string, variants ...string) int {
+	synonyms := d.scope(topic)
+	known := map[string]bool{DefaultCanonicalizer.Key(canonical): true}
+	for c, vs := range synonyms {
+		for _, v := range vs {
+			known[DefaultCanonicalizer.Key(v)] = true
+		}
+		if strings.EqualFold(c, canonical) {
+			canonical = c
+		}
+	}
+	n := 0
+	for _, v := range variants {
+		v = strings.TrimSpace(v)
+		if key := DefaultCanonicalizer.Key(v); len(v) > 0 && !known[key] {
+			known[key] = true
+			synonyms[canonical] = append(synonyms[canonical], v)
+			n++
+		}
+	}
+	sort.Strings(synonyms[canonical])
+	if len(synonyms[canonical]) == 0 {
+		delete(synonyms, canonical)
+	}
+	return n
+}
+
+// SetDictionary gives the quiz the dictionary which ApplyAliases consults for answers its
+// aliases do not change.  Nil removes it.
+func (q *Quiz) SetDictionary(d *Dictionary) {
+	q.dictionary = d
+}
+
+// HasDictionary reports whether the quiz has a dictionary to consult
+func (q *Quiz) HasDictionary() bool {
+	return q.dictionary != nil
+}
+
+// synonyms adds the dictionary's synonyms for a question with the topics to the canonical
+// answers of its variants, those of the topics after those for every question
+func (q *Quiz) synonyms(canonical map[string]string, idxQ int, topics []string) {
+	add := func(synonyms map[string][]string) {
+		for c, variants := range synonyms {
+			for _, v := range variants {
+				canonical[q.AnswerKey(idxQ, v)] = c
+			}
+		}
+	}
+	add(q.dictionary.Synonyms)
+	for _, topic := range topics {
+		add(q.dictionary.Topics[topic])
+	}
+}
+
+// MergedAnswers returns the answers which aliases or synonyms merged into each answer to a
+// question, keyed by the answer they became.  Each is given as the rules rewrote it, since
+// that is what the alias was looked up by, and answers which only the rules changed, or
+// which only differ as the quiz groups them, are left out.  CalcScores must be called first.
+func (q *Quiz) MergedAnswers(idxQ int) map[string][]string {
+	seen := make(map[string]map[string]bool)
+	for _, r := range q.Responses {
+		if idxQ >= len(r.Answers) || idxQ >= len(r.RawAnswers) {
+			continue
+		}
+		raws, answers := q.NormalizedEntries(idxQ, r.RawAnswers[idxQ])
+		for i, a := range answers {
+			if len(a) == 0 {
+				continue
+			}
+			key, raw := q.AnswerKey(idxQ, a), q.rewrite(idxQ, raws[i])
+			if q.AnswerKey(idxQ, raw) == key {
+				continue
+			}
+			answer := q.Questions[idxQ].PopulationCounts[key].OriginalAnswer
+			if seen[answer] == nil {
+				seen[answer] = make(map[string]bool)
+			}
+			seen[answer][raw] = true
+		}
+	}
+	merged := make(map[string][]string, len(seen))
+	for answer, variants := range seen {
+		for v := range variants {
+			merged[answer] = append(merged[answer], v)
+		}
+		sort.Strings(merged[answer])
+	}
+	return merged
+}
@@ -0,0 +1,102 @@
+package sheep
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDictionary_Add(t *testing.T) {
+	d := &Dictionary{Synonyms: map[string][]string{"Saccharin": {"Sweet-n-Low"}}}
+	if n := d.Add("", "saccharin", "sweet n low", "Sacirine", "SACCHARIN", "sacirine", ""); n != 1 {
+		t.Errorf("Add() = %d, want 1", n)
+	}
+	if n := d.Add("presidents", "Abraham Lincoln", "Honest Abe", "Lincoln"); n != 2 {
+		t.Errorf("Add() to a topic = %d, want 2", n)
+	}
+	want := &Dictionary{
+		Synonyms: map[string][]string{"Saccharin": {"Sacirine", "Sweet-n-Low"}},
+		Topics:   map[string]map[string][]string{"presidents": {"Abraham Lincoln": {"Honest Abe", "Lincoln"}}},
+	}
+	if !reflect.DeepEqual(d, want) || d.Count() != 4 {
+		t.Errorf("Add() = %+v, want %+v", d, want)
+	}
+	filename := filepath.Join(t.TempDir(), "sheeptabulator", "dictionary.json")
+	if err := d.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadDictionary(filename)
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadDictionary() = %+v, %v", got, err)
+	}
+	if got, err = ReadDictionary(filepath.Join(t.TempDir(), "missing.json")); err != nil || got.Count() != 0 {
+		t.Errorf("ReadDictionary() of a missing file = %+v, %v", got, err)
+	}
+}
+
+func TestQuiz_ApplyAliases_dictionary(t *testing.T) {
+	q := newTestQuiz([]string{"A sweetener", "A president", "A nickname"},
+		[]string{"Sweet-n-Low", "Honest Abe", "Honest Abe"},
+		[]string{"Saccharin", "Lincoln", "Lincoln"},
+		[]string{"Splenda", "Lincoln", "Abe"},
+	)
+	q.SetDictionary(&Dictionary{
+		Synonyms: map[string][]string{"Saccharin": {"Sweet-n-Low"}, "Sucralose": {"Splenda"}},
+		Topics:   map[string]map[string][]string{"presidents": {"Abraham Lincoln": {"Honest Abe", "Lincoln"}}},
+	})
+	a := &Aliases{Topics: map[string][]string{"A president": {"presidents"}}}
+	a.Add("1", "splenda", "Splenda") // an alias wins over the dictionary
+	n, err := q.ApplyAliases(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range q.Responses {
+		got = append(got, r.Answers...)
+	}
+	want := []string{"Saccharin", "Abraham Lincoln", "Honest Abe", "Saccharin", "Abraham Lincoln", "Lincoln", "Splenda", "Abraham Lincoln", "Abe"}
+	if !reflect.DeepEqual(got, want) || n != 4 {
+		t.Errorf("ApplyAliases() = %d, answers %q, want 4, %q", n, got, want)
+	}
+	if topics := a.QuestionTopics(q, 1); !reflect.DeepEqual(topics, []string{"presidents"}) {
+		t.Errorf("QuestionTopics() = %q", topics)
+	}
+	a.Topics["7"] = []string{"colors"}
+	if _, err = q.ApplyAliases(a); err == nil {
+		t.Errorf("ApplyAliases() with topics for an unknown question did not fail")
+	}
+}
+
+func TestQuiz_MergedAnswers(t *testing.T) {
+	q := newTestQuiz([]string{"A sweetener"},
+		[]string{"Sweet-n-Low"},
+		[]string{"sacirine"},
+		[]string{"Saccharin"},
+		[]string{"saccharin"},
+		[]string{"Sugar"},
+	)
+	a := &Aliases{}
+	a.Add("1", "Sweet-n-Low", "Saccharin")
+	a.Add("1", "Sacirine", "Saccharin")
+	if _, err := q.ApplyAliases(a); err != nil {
+		t.Fatal(err)
+	}
+	q.CalcScores()
+	want := map[string][]string{"Saccharin": {"Sweet-n-Low", "sacirine"}}
+	if got := q.MergedAnswers(0); !reflect.DeepEqual(got, want) {
+		t.Errorf("MergedAnswers() = %q, want %q", got, want)
+	}
+	// Answers only the rules changed are left out, and the others are given as rewritten
+	if err := q.SetRules(&Rules{Questions: map[string][]Rule{"1": {{Find: `^(?i)sugar$`, Replace: "Cane sugar"}, {Find: `-n-`, Replace: " n "}}}}); err != nil {
+		t.Fatal(err)
+	}
+	a.Add("1", "Sweet n Low", "Saccharin")
+	if _, err := q.ApplyAliases(a); err != nil {
+		t.Fatal(err)
+	}
+	q.CalcScores()
+	want = map[string][]string{"Saccharin": {"Sweet n Low", "sacirine"}}
+	if got := q.MergedAnswers(0); !reflect.DeepEqual(got, want) {
+		t.Errorf("MergedAnswers() with rules = %q, want %q", got, want)
+	}
+}
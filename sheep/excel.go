@@ -0,0 +1,88 @@
+package sheep
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// AllSheets as ReadOptions.Sheet reads every sheet with the expected column titles, each
+// sheet being one round of the quiz
+const AllSheets = "*"
+
+// Read responses from XLSX file exported from Microsoft Forms or Google Sheets, or any
+// other workbook the profile describes.
+// Expect row 1 to have column titles in it with rows 2+ having the data (excel table format)
+func readXLSX(filename string, opts ReadOptions) ([]Question, []Response, error) {
+	f, err := excelize.OpenFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	sheets := f.GetSheetList()
+	return ReadSheets(sheets, opts, func(sheet string) ([][]string, error) {
+		return f.GetRows(sheet, excelize.Options{RawCellValue: true})
+	})
+}
+
+// ReadSheets picks the sheets of a workbook to read, getting the rows of a sheet by name
+// with getRows.  With no sheet in the options, the first sheet whose column titles match
+// the profile is read.
+func ReadSheets(sheets []string, opts ReadOptions, getRows func(sheet string) ([][]string, error)) ([]Question, []Response, error) {
+	switch opts.Sheet {
+	case "":
+		reasons := make([]string, 0, len(sheets))
+		for _, sheet := range sheets {
+			rows, err := getRows(sheet)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(rows) == 0 {
+				reasons = append(reasons, fmt.Sprintf("%s: sheet is empty", sheet))
+				continue
+			}
+			if _, _, err = findLayout(rows[0], opts); err != nil {
+				reasons = append(reasons, fmt.Sprintf("%s: %s", sheet, err))
+				continue
+			}
+			return ParseRows(rows, opts)
+		}
+		return nil, nil, fmt.Errorf("no sheet has the expected column titles, sheets found: %s\n\t%s",
+			strings.Join(sheets, ", "), strings.Join(reasons, "\n\t"))
+	case AllSheets:
+		rounds := make([]round, 0, len(sheets))
+		for _, sheet := range sheets {
+			rows, err := getRows(sheet)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(rows) == 0 {
+				continue
+			}
+			if _, _, err = findLayout(rows[0], opts); err != nil {
+				continue
+			}
+			questions, responses, err := ParseRows(rows, opts)
+			if err != nil {
+				return nil, nil, fmt.Errorf("sheet %s: %s", sheet, err)
+			}
+			rounds = append(rounds, round{questions: questions, responses: responses})
+		}
+		if len(rounds) == 0 {
+			return nil, nil, fmt.Errorf("no sheet has the expected column titles, sheets found: %s", strings.Join(sheets, ", "))
+		}
+		questions, responses := joinRounds(rounds)
+		return questions, responses, nil
+	}
+	for _, sheet := range sheets {
+		if sheet == opts.Sheet {
+			rows, err := getRows(sheet)
+			if err != nil {
+				return nil, nil, err
+			}
+			return ParseRows(rows, opts)
+		}
+	}
+	return nil, nil, fmt.Errorf("there is no sheet named '%s', sheets found: %s", opts.Sheet, strings.Join(sheets, ", "))
+}
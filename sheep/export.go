@@ -0,0 +1,273 @@
+package sheep
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// SummarySheet is the sheet of an exported workbook which lists every changed answer
+const SummarySheet = "Normalization"
+
+// AnswerChange is an answer to a question which normalization changed, and how many
+// responses gave it
+type AnswerChange struct {
+	Question int // index of the question
+	Original string
+	Answer   string // "" if the answer was blanked
+	Count    int
+}
+
+// AnswerChanges lists the answers which aliases and the other normalizations changed,
+// by question and then by the answer they became
+func (q *Quiz) AnswerChanges() []AnswerChange {
+	counts := make(map[AnswerChange]int)
+	for _, r := range q.Responses {
+		for i, raw := range r.RawAnswers {
+			if i < len(r.Answers) && raw != r.Answers[i] {
+				counts[AnswerChange{Question: i, Original: raw, Answer: r.Answers[i]}]++
+			}
+		}
+	}
+	changes := make([]AnswerChange, 0, len(counts))
+	for c, n := range counts {
+		c.Count = n
+		changes = append(changes, c)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if a.Question != b.Question {
+			return a.Question < b.Question
+		}
+		if a.Answer != b.Answer {
+			return a.Answer < b.Answer
+		}
+		return a.Original < b.Original
+	})
+	return changes
+}
+
+// NormalizedFileName is the default name of the exported copy of a response file:
+// responses.xlsx is exported to responses.normalized.xlsx.
+func NormalizedFileName(filename string) string {
+	return sidecarFile(filename, ".normalized.xlsx")
+}
+
+// ExportNormalized writes an XLSX workbook with the normalized answers in place of the
+// answers as read.  Every changed cell is highlighted with the answer as read in a comment,
+// and a summary sheet lists the changes to each question.  An XLSX response file is copied
+// with its answer cells changed; the responses from any other input are written to a new
+// workbook.  The number of changed cells is returned.
+func (q *Quiz) ExportNormalized(src, dst string, opts ReadOptions) (int, error) {
+	changes := q.AnswerChanges()
+	changed := make(map[int]map[string]string)
+	for _, c := range changes {
+		if changed[c.Question] == nil {
+			changed[c.Question] = make(map[string]string)
+		}
+		changed[c.Question][c.Original] = c.Answer
+	}
+	var (
+		f   *excelize.File
+		n   int
+		err error
+	)
+	if isXLSXFile(src) {
+		f, n, err = q.exportCopy(src, changed, opts)
+	} else {
+		f, n, err = q.exportNew(changed)
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	if err = q.writeSummary(f, changes); err != nil {
+		return 0, err
+	}
+	return n, f.SaveAs(dst)
+}
+
+func isXLSXFile(filename string) bool {
+	if !strings.EqualFold(filepath.Ext(filename), ".xlsx") {
+		return false
+	}
+	_, err := os.Stat(filename)
+	return err == nil
+}
+
+// exportCopy opens the workbook and changes the answer cells of the sheets the responses
+// were read from, choosing the sheets the same way they were chosen when reading
+func (q *Quiz) exportCopy(src string, changed map[int]map[string]string, opts ReadOptions) (*excelize.File, int, error) {
+	f, err := excelize.OpenFile(src)
+	if err != nil {
+		return nil, 0, err
+	}
+	style, err := changedStyle(f)
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+	// The responses no longer hold what moderation removed, so find it in the cells again
+	removed := make(map[int]map[string]string)
+	for _, r := range q.Removed {
+		if removed[r.Question] == nil {
+			removed[r.Question] = make(map[string]string)
+		}
+		removed[r.Question][strings.TrimSpace(r.Answer)] = r.Replacement
+	}
+	n, offset := 0, 0
+	for _, sheet := range f.GetSheetList() {
+		if opts.Sheet != "" && opts.Sheet != AllSheets && opts.Sheet != sheet {
+			continue
+		}
+		rows, err := f.GetRows(sheet, excelize.Options{RawCellValue: true})
+		if err != nil {
+			f.Close()
+			return nil, 0, err
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		layout, _, err := findLayout(rows[0], opts)
+		if err != nil {
+			continue
+		}
+		for idxRow, row := range rows[1:] {
+			for k, col := range layout.questions {
+				if col >= len(row) {
+					continue
+				}
+				raw := strings.TrimSpace(row[col])
+				answer, ok := changed[offset+k][raw]
+				note := changeNote(raw, answer)
+				if replacement, removed := removed[offset+k][raw]; removed {
+					answer, ok, note = replacement, true, "Removed by moderation"
+					if a, normalized := changed[offset+k][replacement]; normalized {
+						answer = a // what was left of a list was normalized too
+					}
+				}
+				if !ok {
+					continue
+				}
+				if err = markChange(f, sheet, col+1, idxRow+2, answer, note, style); err != nil {
+					f.Close()
+					return nil, 0, err
+				}
+				n++
+			}
+		}
+		if opts.Sheet != AllSheets {
+			break
+		}
+		offset += len(layout.questions)
+	}
+	return f, n, nil
+}
+
+// exportNew writes the responses to a new workbook laid out like a Microsoft Forms export
+func (q *Quiz) exportNew(changed map[int]map[string]string) (*excelize.File, int, error) {
+	const sheet = "Responses"
+	f := excelize.NewFile()
+	if err := f.SetSheetName(f.GetSheetName(0), sheet); err != nil {
+		return nil, 0, err
+	}
+	style, err := changedStyle(f)
+	if err != nil {
+		return nil, 0, err
+	}
+	header := []interface{}{"Completion time", "Email", "Name"}
+	for _, question := range q.Questions {
+		title := question.Text
+		if question.BonusQuestion {
+			title = fmt.Sprintf("%s [%s]", title, question.BonusSpec())
+		}
+		header = append(header, title)
+	}
+	if err = f.SetSheetRow(sheet, "A1", &header); err != nil {
+		return nil, 0, err
+	}
+	n := 0
+	for idxR, r := range q.Responses {
+		row := []interface{}{r.Completed, r.Email, r.Name}
+		for i, answer := range r.Answers {
+			if i < len(r.RawAnswers) {
+				answer = r.RawAnswers[i]
+			}
+			row = append(row, answer)
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, idxR+2)
+		if err = f.SetSheetRow(sheet, cell, &row); err != nil {
+			return nil, 0, err
+		}
+		for i, raw := range r.RawAnswers {
+			if answer, ok := changed[i][raw]; ok {
+				if err = markChange(f, sheet, i+4, idxR+2, answer, changeNote(raw, answer), style); err != nil {
+					return nil, 0, err
+				}
+				n++
+			}
+		}
+	}
+	return f, n, nil
+}
+
+func changedStyle(f *excelize.File) (int, error) {
+	return f.NewStyle(&excelize.Style{Fill: excelize.Fill{Type: "pattern", Color: []string{"FFEB9C"}, Pattern: 1}})
+}
+
+// changeNote is the comment on a changed cell
+func changeNote(raw, answer string) string {
+	if len(answer) == 0 {
+		return "Was: " + raw + " (blanked)"
+	}
+	return "Was: " + raw
+}
+
+// markChange puts the normalized answer in a cell, highlighted, with a note in a comment
+func markChange(f *excelize.File, sheet string, col, row int, answer, note string, style int) error {
+	cell, err := excelize.CoordinatesToCellName(col, row)
+	if err != nil {
+		return err
+	}
+	if err = f.SetCellStr(sheet, cell, answer); err != nil {
+		return err
+	}
+	if err = f.SetCellStyle(sheet, cell, cell, style); err != nil {
+		return err
+	}
+	return f.AddComment(sheet, excelize.Comment{Cell: cell, Author: "sheeptabulator", Text: note})
+}
+
+// writeSummary adds the summary sheet, replacing any left from an earlier export
+func (q *Quiz) writeSummary(f *excelize.File, changes []AnswerChange) error {
+	if idx, _ := f.GetSheetIndex(SummarySheet); idx >= 0 {
+		if err := f.DeleteSheet(SummarySheet); err != nil {
+			return err
+		}
+	}
+	if _, err := f.NewSheet(SummarySheet); err != nil {
+		return err
+	}
+	rows := [][]interface{}{{"#", "Question", "Answer", "Was", "Responses"}}
+	for _, c := range changes {
+		answer := c.Answer
+		if len(answer) == 0 {
+			answer = "(blank)"
+		}
+		rows = append(rows, []interface{}{c.Question + 1, q.Questions[c.Question].Text, answer, c.Original, c.Count})
+	}
+	if len(changes) == 0 {
+		rows = append(rows, []interface{}{nil, "No answers were changed"})
+	}
+	for i := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := f.SetSheetRow(SummarySheet, cell, &rows[i]); err != nil {
+			return err
+		}
+	}
+	return f.SetColWidth(SummarySheet, "B", "D", 30)
+}
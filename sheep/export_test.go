@@ -0,0 +1,133 @@
+package sheep
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestQuiz_ExportNormalized(t *testing.T) {
+	xlsx := writeTestXLSX(t, "responses.xlsx", [][]string{
+		microsoftHeader,
+		{"1", "45366.41", "45366.42", "a@acme.com", "Al", "Sweet-n-Low", "Red"},
+		{"2", "45366.41", "45366.43", "b@acme.com", "Bo", "Saccharin", "red"},
+		{"3", "45366.41", "45366.44", "c@acme.com", "Cy", "sweet-n-low", "Bleu"},
+	})
+	csv := writeTestFile(t, "responses.csv", "Timestamp,Email Address,A sweetener,A color\n"+
+		"3/15/2024 10:00:00,a@acme.com,Sweet-n-Low,Red\n"+
+		"3/15/2024 10:01:00,b@acme.com,Saccharin,red\n"+
+		"3/15/2024 10:02:00,c@acme.com,sweet-n-low,Bleu\n")
+	tests := []struct {
+		name  string
+		input string
+		src   string
+		sheet string
+		col   int // column of the first question
+	}{
+		{"copy", xlsx, xlsx, "Sheet1", 6},
+		{"new workbook", csv, "", "Responses", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var q Quiz
+			if err := q.ReadResponses(tt.input, ReadOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			a := &Aliases{}
+			a.Add("1", "Sweet-n-Low", "Saccharin")
+			a.Add("2", "Bleu", "")
+			if _, err := q.ApplyAliases(a); err != nil {
+				t.Fatal(err)
+			}
+			dst := filepath.Join(t.TempDir(), "normalized.xlsx")
+			n, err := q.ExportNormalized(tt.src, dst, ReadOptions{})
+			if err != nil {
+				t.Fatalf("ExportNormalized() error = %v", err)
+			}
+			if n != 3 {
+				t.Errorf("ExportNormalized() = %d changed, want 3", n)
+			}
+			f, err := excelize.OpenFile(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			for _, c := range []struct {
+				col, row    int
+				value, note string
+			}{
+				{tt.col, 2, "Saccharin", "Was: Sweet-n-Low"},
+				{tt.col, 3, "Saccharin", ""},
+				{tt.col, 4, "Saccharin", "Was: sweet-n-low"},
+				{tt.col + 1, 3, "red", ""},
+				{tt.col + 1, 4, "", "Was: Bleu (blanked)"},
+			} {
+				cell, _ := excelize.CoordinatesToCellName(c.col, c.row)
+				if v, _ := f.GetCellValue(tt.sheet, cell); v != c.value {
+					t.Errorf("%s = %q, want %q", cell, v, c.value)
+				}
+				note := ""
+				comments, _ := f.GetComments(tt.sheet)
+				for _, comment := range comments {
+					if comment.Cell == cell {
+						note = comment.Text
+					}
+				}
+				if note != c.note {
+					t.Errorf("comment on %s = %q, want %q", cell, note, c.note)
+				}
+			}
+			rows, err := f.GetRows(SummarySheet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rows) != 4 || rows[1][2] != "Saccharin" || rows[1][3] != "Sweet-n-Low" || rows[3][2] != "(blank)" {
+				t.Errorf("summary = %q", rows)
+			}
+		})
+	}
+}
+
+func TestNormalizedFileName(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     string
+	}{
+		{"responses.xlsx", "responses.normalized.xlsx"},
+		{"dir/quiz.csv", "dir/quiz.normalized.xlsx"},
+		{"sheets://abc/Form1", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizedFileName(tt.filename); got != tt.want {
+			t.Errorf("NormalizedFileName(%s) = %s, want %s", tt.filename, got, tt.want)
+		}
+	}
+}
+
+func TestQuiz_ExportNormalized_moderated(t *testing.T) {
+	xlsx := writeTestXLSX(t, "responses.xlsx", [][]string{
+		microsoftHeader,
+		{"1", "45366.41", "45366.42", "a@acme.com", "Al", "Darn sugar", "Red"},
+	})
+	var q Quiz
+	if err := q.ReadResponses(xlsx, ReadOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Moderate(&Moderation{Blocklist: []string{"darn"}}); err != nil {
+		t.Fatal(err)
+	}
+	dst := filepath.Join(t.TempDir(), "normalized.xlsx")
+	if _, err := q.ExportNormalized(xlsx, dst, ReadOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	comments, _ := f.GetComments("Sheet1")
+	if v, _ := f.GetCellValue("Sheet1", "F2"); v != "" || len(comments) != 1 || comments[0].Text != "Removed by moderation" {
+		t.Errorf("F2 = %q, comments = %+v", v, comments)
+	}
+}
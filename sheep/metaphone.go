@@ -0,0 +1,529 @@
+package sheep
+
+import (
+	"strings"
+)
+
+// DoubleMetaphone returns the primary and alternate Double Metaphone codes of a word, after
+// Lawrence Philips' algorithm.  Words which sound alike, such as "Jon" and "John" or
+// "Schmidt" and "Smith", share a code.  The codes are not cut to four letters, so longer
+// names are told apart.
+func DoubleMetaphone(word string) (primary, alternate string) {
+	m := &metaphone{value: []rune(strings.ToUpper(strings.TrimSpace(word)))}
+	if len(m.value) == 0 {
+		return "", ""
+	}
+	m.slavoGermanic = strings.ContainsAny(string(m.value), "WK") || strings.Contains(string(m.value), "CZ")
+	index := 0
+	if m.has(0, 2, "GN", "KN", "PN", "WR", "PS") {
+		index = 1
+	}
+	for index < len(m.value) {
+		switch m.at(index) {
+		case 'A', 'E', 'I', 'O', 'U', 'Y':
+			if index == 0 {
+				m.add("A")
+			}
+			index++
+		case 'B':
+			m.add("P")
+			index = m.skip(index, 'B')
+		case 'Ç':
+			m.add("S")
+			index++
+		case 'C':
+			index = m.c(index)
+		case 'D':
+			index = m.d(index)
+		case 'F':
+			m.add("F")
+			index = m.skip(index, 'F')
+		case 'G':
+			index = m.g(index)
+		case 'H':
+			index = m.h(index)
+		case 'J':
+			index = m.j(index)
+		case 'K':
+			m.add("K")
+			index = m.skip(index, 'K')
+		case 'L':
+			index = m.l(index)
+		case 'M':
+			m.add("M")
+			if m.m0(index) {
+				index += 2
+			} else {
+				index++
+			}
+		case 'N':
+			m.add("N")
+			index = m.skip(index, 'N')
+		case 'Ñ':
+			m.add("N")
+			index++
+		case 'P':
+			index = m.p(index)
+		case 'Q':
+			m.add("K")
+			index = m.skip(index, 'Q')
+		case 'R':
+			index = m.r(index)
+		case 'S':
+			index = m.s(index)
+		case 'T':
+			index = m.t(index)
+		case 'V':
+			m.add("F")
+			index = m.skip(index, 'V')
+		case 'W':
+			index = m.w(index)
+		case 'X':
+			index = m.x(index)
+		case 'Z':
+			index = m.z(index)
+		default:
+			index++
+		}
+	}
+	return m.primary.String(), m.alternate.String()
+}
+
+type metaphone struct {
+	value              []rune
+	slavoGermanic      bool
+	primary, alternate strings.Builder
+}
+
+// add appends to both codes, or to the primary and alternate codes separately
+func (m *metaphone) add(codes ...string) {
+	m.primary.WriteString(codes[0])
+	if len(codes) > 1 {
+		m.alternate.WriteString(codes[1])
+	} else {
+		m.alternate.WriteString(codes[0])
+	}
+}
+
+func (m *metaphone) at(i int) rune {
+	if i < 0 || i >= len(m.value) {
+		return 0
+	}
+	return m.value[i]
+}
+
+// has reports whether the n letters at start are any of the choices
+func (m *metaphone) has(start, n int, choices ...string) bool {
+	if start < 0 || start+n > len(m.value) {
+		return false
+	}
+	s := string(m.value[start : start+n])
+	for _, c := range choices {
+		if s == c {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *metaphone) vowel(i int) bool {
+	return strings.ContainsRune("AEIOUY", m.at(i))
+}
+
+func (m *metaphone) last() int {
+	return len(m.value) - 1
+}
+
+// skip moves past a letter and a double of it
+func (m *metaphone) skip(index int, r rune) int {
+	if m.at(index+1) == r {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (m *metaphone) c(index int) int {
+	switch {
+	case m.c0(index):
+		m.add("K")
+		return index + 2
+	case index == 0 && m.has(index, 6, "CAESAR"):
+		m.add("S")
+		return index + 2
+	case m.has(index, 2, "CH"):
+		return m.ch(index)
+	case m.has(index, 2, "CZ") && !m.has(index-2, 4, "WICZ"):
+		m.add("S", "X")
+		return index + 2
+	case m.has(index+1, 3, "CIA"):
+		m.add("X")
+		return index + 3
+	case m.has(index, 2, "CC") && !(index == 1 && m.at(0) == 'M'):
+		return m.cc(index)
+	case m.has(index, 2, "CK", "CG", "CQ"):
+		m.add("K")
+		return index + 2
+	case m.has(index, 2, "CI", "CE", "CY"):
+		if m.has(index, 3, "CIO", "CIE", "CIA") {
+			m.add("S", "X")
+		} else {
+			m.add("S")
+		}
+		return index + 2
+	}
+	m.add("K")
+	switch {
+	case m.has(index+1, 2, " C", " Q", " G"):
+		return index + 3
+	case m.has(index+1, 1, "C", "K", "Q") && !m.has(index+1, 2, "CE", "CI"):
+		return index + 2
+	}
+	return index + 1
+}
+
+func (m *metaphone) c0(index int) bool {
+	if m.has(index, 4, "CHIA") {
+		return true
+	}
+	if index <= 1 || m.vowel(index-2) || !m.has(index-1, 3, "ACH") {
+		return false
+	}
+	c := m.at(index + 2)
+	return (c != 'I' && c != 'E') || m.has(index-2, 6, "BACHER", "MACHER")
+}
+
+func (m *metaphone) cc(index int) int {
+	if m.has(index+2, 1, "I", "E", "H") && !m.has(index+2, 2, "HU") {
+		if (index == 1 && m.at(index-1) == 'A') || m.has(index-1, 5, "UCCEE", "UCCES") {
+			m.add("KS")
+		} else {
+			m.add("X")
+		}
+		return index + 3
+	}
+	m.add("K")
+	return index + 2
+}
+
+func (m *metaphone) ch(index int) int {
+	switch {
+	case index > 0 && m.has(index, 4, "CHAE"):
+		m.add("K", "X")
+	case m.ch0(index), m.ch1(index):
+		m.add("K")
+	case index > 0 && m.has(0, 2, "MC"):
+		m.add("K")
+	case index > 0:
+		m.add("X", "K")
+	default:
+		m.add("X")
+	}
+	return index + 2
+}
+
+func (m *metaphone) ch0(index int) bool {
+	if index != 0 {
+		return false
+	}
+	if !m.has(index+1, 5, "HARAC", "HARIS") && !m.has(index+1, 3, "HOR", "HYM", "HIA", "HEM") {
+		return false
+	}
+	return !m.has(0, 5, "CHORE")
+}
+
+func (m *metaphone) ch1(index int) bool {
+	return m.has(0, 4, "VAN ", "VON ") || m.has(0, 3, "SCH") ||
+		m.has(index-2, 6, "ORCHES", "ARCHIT", "ORCHID") ||
+		m.has(index+2, 1, "T", "S") ||
+		((m.has(index-1, 1, "A", "O", "U", "E") || index == 0) &&
+			(m.has(index+2, 1, "L", "R", "N", "M", "B", "H", "F", "V", "W", " ") || index+1 == m.last()))
+}
+
+func (m *metaphone) d(index int) int {
+	switch {
+	case m.has(index, 2, "DG"):
+		if m.has(index+2, 1, "I", "E", "Y") {
+			m.add("J")
+			return index + 3
+		}
+		m.add("TK")
+		return index + 2
+	case m.has(index, 2, "DT", "DD"):
+		m.add("T")
+		return index + 2
+	}
+	m.add("T")
+	return index + 1
+}
+
+func (m *metaphone) g(index int) int {
+	next := m.at(index + 1)
+	switch {
+	case next == 'H':
+		return m.gh(index)
+	case next == 'N':
+		switch {
+		case index == 1 && m.vowel(0) && !m.slavoGermanic:
+			m.add("KN", "N")
+		case !m.has(index+2, 2, "EY") && m.at(index+1) != 'Y' && !m.slavoGermanic:
+			m.add("N", "KN")
+		default:
+			m.add("KN")
+		}
+		return index + 2
+	case m.has(index+1, 2, "LI") && !m.slavoGermanic:
+		m.add("KL", "L")
+		return index + 2
+	case index == 0 && (next == 'Y' || m.has(index+1, 2, "ES", "EP", "EB", "EL", "EY", "IB", "IL", "IN", "IE", "EI", "ER")):
+		m.add("K", "J")
+		return index + 2
+	case (m.has(index+1, 2, "ER") || next == 'Y') && !m.has(0, 6, "DANGER", "RANGER", "MANGER") &&
+		!m.has(index-1, 1, "E", "I") && !m.has(index-1, 3, "RGY", "OGY"):
+		m.add("K", "J")
+		return index + 2
+	case m.has(index+1, 1, "E", "I", "Y") || m.has(index-1, 4, "AGGI", "OGGI"):
+		switch {
+		case m.has(0, 4, "VAN ", "VON ") || m.has(0, 3, "SCH") || m.has(index+1, 2, "ET"):
+			m.add("K")
+		case m.has(index+1, 3, "IER"):
+			m.add("J")
+		default:
+			m.add("J", "K")
+		}
+		return index + 2
+	case next == 'G':
+		m.add("K")
+		return index + 2
+	}
+	m.add("K")
+	return index + 1
+}
+
+func (m *metaphone) gh(index int) int {
+	switch {
+	case index > 0 && !m.vowel(index-1):
+		m.add("K")
+	case index == 0:
+		if m.at(index+2) == 'I' {
+			m.add("J")
+		} else {
+			m.add("K")
+		}
+	case (index > 1 && m.has(index-2, 1, "B", "H", "D")) || (index > 2 && m.has(index-3, 1, "B", "H", "D")) ||
+		(index > 3 && m.has(index-4, 1, "B", "H")):
+		// silent, as in "hugh" and "bough"
+	case index > 2 && m.at(index-1) == 'U' && m.has(index-3, 1, "C", "G", "L", "R", "T"):
+		m.add("F")
+	case index > 0 && m.at(index-1) != 'I':
+		m.add("K")
+	}
+	return index + 2
+}
+
+func (m *metaphone) h(index int) int {
+	if (index == 0 || m.vowel(index-1)) && m.vowel(index+1) {
+		m.add("H")
+		return index + 2
+	}
+	return index + 1
+}
+
+func (m *metaphone) j(index int) int {
+	if m.has(index, 4, "JOSE") || m.has(0, 4, "SAN ") {
+		if (index == 0 && m.at(index+4) == ' ') || len(m.value) == 4 || m.has(0, 4, "SAN ") {
+			m.add("H")
+		} else {
+			m.add("J", "H")
+		}
+		return index + 1
+	}
+	switch {
+	case index == 0:
+		m.add("J", "A")
+	case m.vowel(index-1) && !m.slavoGermanic && (m.at(index+1) == 'A' || m.at(index+1) == 'O'):
+		m.add("J", "H")
+	case index == m.last():
+		m.add("J", "")
+	case !m.has(index+1, 1, "L", "T", "K", "S", "N", "M", "B", "Z") && !m.has(index-1, 1, "S", "K", "L"):
+		m.add("J")
+	}
+	return m.skip(index, 'J')
+}
+
+func (m *metaphone) l(index int) int {
+	if m.at(index+1) != 'L' {
+		m.add("L")
+		return index + 1
+	}
+	if m.l0(index) {
+		m.add("L", "")
+	} else {
+		m.add("L")
+	}
+	return index + 2
+}
+
+func (m *metaphone) l0(index int) bool {
+	if index == len(m.value)-3 && m.has(index-1, 4, "ILLO", "ILLA", "ALLE") {
+		return true
+	}
+	return (m.has(len(m.value)-2, 2, "AS", "OS") || m.has(m.last(), 1, "A", "O")) && m.has(index-1, 4, "ALLE")
+}
+
+func (m *metaphone) m0(index int) bool {
+	if m.at(index+1) == 'M' {
+		return true
+	}
+	return m.has(index-1, 3, "UMB") && (index+1 == m.last() || m.has(index+2, 2, "ER"))
+}
+
+func (m *metaphone) p(index int) int {
+	if m.at(index+1) == 'H' {
+		m.add("F")
+		return index + 2
+	}
+	m.add("P")
+	if m.has(index+1, 1, "P", "B") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (m *metaphone) r(index int) int {
+	if index == m.last() && !m.slavoGermanic && m.has(index-2, 2, "IE") && !m.has(index-4, 2, "ME", "MA") {
+		m.add("", "R")
+	} else {
+		m.add("R")
+	}
+	return m.skip(index, 'R')
+}
+
+func (m *metaphone) s(index int) int {
+	switch {
+	case m.has(index-1, 3, "ISL", "YSL"):
+		// silent, as in "island" and "carlisle"
+		return index + 1
+	case index == 0 && m.has(index, 5, "SUGAR"):
+		m.add("X", "S")
+		return index + 1
+	case m.has(index, 2, "SH"):
+		if m.has(index+1, 4, "HEIM", "HOEK", "HOLM", "HOLZ") {
+			m.add("S")
+		} else {
+			m.add("X")
+		}
+		return index + 2
+	case m.has(index, 3, "SIO", "SIA") || m.has(index, 4, "SIAN"):
+		if m.slavoGermanic {
+			m.add("S")
+		} else {
+			m.add("S", "X")
+		}
+		return index + 3
+	case (index == 0 && m.has(index+1, 1, "M", "N", "L", "W")) || m.has(index+1, 1, "Z"):
+		m.add("S", "X")
+		if m.has(index+1, 1, "Z") {
+			return index + 2
+		}
+		return index + 1
+	case m.has(index, 2, "SC"):
+		return m.sc(index)
+	}
+	if index == m.last() && m.has(index-2, 2, "AI", "OI") {
+		m.add("", "S")
+	} else {
+		m.add("S")
+	}
+	if m.has(index+1, 1, "S", "Z") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (m *metaphone) sc(index int) int {
+	switch {
+	case m.at(index+2) == 'H':
+		switch {
+		case m.has(index+3, 2, "ER", "EN"):
+			m.add("X", "SK")
+		case m.has(index+3, 2, "OO", "UY", "ED", "EM"):
+			m.add("SK")
+		case index == 0 && !m.vowel(3) && m.at(3) != 'W':
+			m.add("X", "S")
+		default:
+			m.add("X")
+		}
+	case m.has(index+2, 1, "I", "E", "Y"):
+		m.add("S")
+	default:
+		m.add("SK")
+	}
+	return index + 3
+}
+
+func (m *metaphone) t(index int) int {
+	switch {
+	case m.has(index, 4, "TION"), m.has(index, 3, "TIA", "TCH"):
+		m.add("X")
+		return index + 3
+	case m.has(index, 2, "TH") || m.has(index, 3, "TTH"):
+		if m.has(index+2, 2, "OM", "AM") || m.has(0, 4, "VAN ", "VON ") || m.has(0, 3, "SCH") {
+			m.add("T")
+		} else {
+			m.add("0", "T")
+		}
+		return index + 2
+	}
+	m.add("T")
+	if m.has(index+1, 1, "T", "D") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (m *metaphone) w(index int) int {
+	switch {
+	case m.has(index, 2, "WR"):
+		m.add("R")
+		return index + 2
+	case index == 0 && (m.vowel(index+1) || m.has(index, 2, "WH")):
+		if m.vowel(index + 1) {
+			m.add("A", "F")
+		} else {
+			m.add("A")
+		}
+	case (index == m.last() && m.vowel(index-1)) || m.has(index-1, 5, "EWSKI", "EWSKY", "OWSKI", "OWSKY") || m.has(0, 3, "SCH"):
+		m.add("", "F")
+	case m.has(index, 4, "WICZ", "WITZ"):
+		m.add("TS", "FX")
+		return index + 4
+	}
+	return index + 1
+}
+
+func (m *metaphone) x(index int) int {
+	if index == 0 {
+		m.add("S")
+		return index + 1
+	}
+	if !(index == m.last() && (m.has(index-3, 3, "IAU", "EAU") || m.has(index-2, 2, "AU", "OU"))) {
+		m.add("KS")
+	}
+	if m.has(index+1, 1, "C", "X") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (m *metaphone) z(index int) int {
+	if m.at(index+1) == 'H' {
+		m.add("J")
+		return index + 2
+	}
+	if m.has(index+1, 2, "ZO", "ZI", "ZA") || (m.slavoGermanic && index > 0 && m.at(index-1) != 'T') {
+		m.add("S", "TS")
+	} else {
+		m.add("S")
+	}
+	return m.skip(index, 'Z')
+}
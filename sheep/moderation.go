@@ -0,0 +1,166 @@
+package sheep
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Moderation removes unacceptable answers before they are scored or shown.  An answer
+// containing a word or phrase of the blocklist is replaced, and the answers listed in
+// Blanks are blanked for that question only.
+type Moderation struct {
+	Blocklist   []string            `json:"blocklist,omitempty"`
+	Replacement string              `json:"replacement,omitempty"` // answer in place of a blocked one; "" blanks it
+	Blanks      map[string][]string `json:"blanks,omitempty"`      // question number or text to answers to blank
+}
+
+// Removal is an answer which moderation removed, kept for the quiz master's report
+type Removal struct {
+	Question    int // index of the question
+	Email       string
+	Name        string
+	Answer      string // the answer as given
+	Replacement string
+	Reason      string
+}
+
+// moderationCanon makes answers and blocked phrases comparable word by word
+var moderationCanon = NewCanonicalizer(NormNFKC, NormDiacritics, NormPunctuation, NormWhitespace)
+
+// ModerationFileName is the name of the moderation file kept next to a response file:
+// responses.xlsx has responses.moderation.json.
+func ModerationFileName(filename string) string {
+	return sidecarFile(filename, ".moderation.json")
+}
+
+// ReadModeration reads a moderation file.  A file which does not exist moderates nothing.
+func ReadModeration(filename string) (*Moderation, error) {
+	m := &Moderation{}
+	if err := readJSONFile(filename, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ReadBlocklist reads a blocklist file of one word or phrase per line.  Blank lines and
+// lines starting with # are skipped.
+func ReadBlocklist(filename string) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var phrases []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) > 0 && !strings.HasPrefix(line, "#") {
+			phrases = append(phrases, line)
+		}
+	}
+	return phrases, scanner.Err()
+}
+
+// Empty reports whether the moderation would change nothing
+func (m *Moderation) Empty() bool {
+	return len(m.Blocklist) == 0 && len(m.Blanks) == 0
+}
+
+// blocked returns the blocklist phrase found in an answer, matching whole words regardless
+// of case, accents, and punctuation
+func (m *Moderation) blocked(answer string) (string, bool) {
+	words := " " + moderationCanon.Key(answer) + " "
+	for _, phrase := range m.Blocklist {
+		if key := moderationCanon.Key(phrase); len(key) > 0 && strings.Contains(words, " "+key+" ") {
+			return phrase, true
+		}
+	}
+	return "", false
+}
+
+// Moderate blanks the answers listed in Blanks and replaces the answers containing a
+// blocked phrase, and returns what was removed.  Each entry of an answer to a multi-answer
+// question is moderated on its own, and only the entries removed are dropped from the list.
+// The answers as read are changed as well, so a removed answer is not kept anywhere; it
+// must run before aliases are applied.
+func (q *Quiz) Moderate(m *Moderation) ([]Removal, error) {
+	blanks := make([]map[string]bool, len(q.Questions))
+	for key, answers := range m.Blanks {
+		idxQ, err := q.FindQuestion(key)
+		if err != nil {
+			return nil, fmt.Errorf("moderation: %s", err)
+		}
+		if blanks[idxQ] == nil {
+			blanks[idxQ] = make(map[string]bool)
+		}
+		for _, a := range answers {
+			blanks[idxQ][q.AnswerKey(idxQ, a)] = true
+		}
+	}
+	var removed []Removal
+	for idxR := range q.Responses {
+		r := &q.Responses[idxR]
+		for i, a := range r.Answers {
+			if len(a) == 0 {
+				continue
+			}
+			var replacement, reason string
+			if i < len(q.Questions) && q.Questions[i].Multi {
+				var kept, reasons []string
+				for _, e := range splitEntries(a) {
+					if why, _, ok := m.check(q, blanks, i, e); ok {
+						reasons = append(reasons, fmt.Sprintf("%s (%s)", why, e))
+					} else {
+						kept = append(kept, e)
+					}
+				}
+				if len(reasons) == 0 {
+					continue
+				}
+				replacement, reason = strings.Join(kept, ", "), strings.Join(reasons, ", ")
+			} else if why, with, ok := m.check(q, blanks, i, a); ok {
+				replacement, reason = with, why
+			} else {
+				continue
+			}
+			removed = append(removed, Removal{Question: i, Email: r.Email, Name: r.Name, Answer: a, Replacement: replacement, Reason: reason})
+			r.Answers[i] = replacement
+			if i < len(r.RawAnswers) {
+				r.RawAnswers[i] = replacement
+			}
+		}
+	}
+	sort.SliceStable(removed, func(i, j int) bool { return removed[i].Question < removed[j].Question })
+	q.Removed = append(q.Removed, removed...)
+	return removed, nil
+}
+
+// check reports whether an answer to a question is removed, why, and what replaces it
+func (m *Moderation) check(q *Quiz, blanks []map[string]bool, idxQ int, answer string) (reason, replacement string, removed bool) {
+	if idxQ < len(blanks) && blanks[idxQ][q.AnswerKey(idxQ, answer)] {
+		return "blanked", "", true
+	}
+	if phrase, ok := m.blocked(answer); ok {
+		return "blocked: " + phrase, m.Replacement, true
+	}
+	return "", "", false
+}
+
+// WriteModerationReport writes who gave each removed answer, by question
+func (q *Quiz) WriteModerationReport(w io.Writer, removed []Removal) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	last := -1
+	for _, r := range removed {
+		if r.Question != last {
+			fmt.Fprintf(tw, "Question #%d -- %s\n", r.Question+1, q.Questions[r.Question].Text)
+			last = r.Question
+		}
+		fmt.Fprintf(tw, "\t%s\t%s\t%s\t%s\n", r.Name, r.Email, r.Answer, r.Reason)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,89 @@
+package sheep
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestQuiz_Moderate(t *testing.T) {
+	tests := []struct {
+		name        string
+		m           Moderation
+		wantAnswers []string // answers to question 1 after moderation
+		wantReasons []string
+	}{
+		{"blocklist", Moderation{Blocklist: []string{"darn", "boss man"}},
+			[]string{"", "Darned", "", "Sugar"}, []string{"blocked: darn", "blocked: boss man"}},
+		{"replacement", Moderation{Blocklist: []string{"DARN"}, Replacement: "***"},
+			[]string{"***", "Darned", "Ask the Boss-Man", "Sugar"}, []string{"blocked: DARN"}},
+		{"blanks", Moderation{Blanks: map[string][]string{"A sweetener": {"sugar"}}},
+			[]string{"Darn Sugar", "Darned", "Ask the Boss-Man", ""}, []string{"blanked"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newTestQuiz([]string{"A sweetener", "A color"},
+				[]string{"Darn Sugar", "Red"},
+				[]string{"Darned", "Blue"},
+				[]string{"Ask the Boss-Man", "Red"},
+				[]string{"Sugar", "darn"},
+			)
+			q.Responses[0].RawAnswers = []string{"Darn Sugar", "Red"}
+			removed, err := q.Moderate(&tt.m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var answers, reasons []string
+			for _, r := range q.Responses {
+				answers = append(answers, r.Answers[0])
+			}
+			for _, r := range removed {
+				if r.Question == 0 {
+					reasons = append(reasons, r.Reason)
+				}
+			}
+			if !reflect.DeepEqual(answers, tt.wantAnswers) || !reflect.DeepEqual(reasons, tt.wantReasons) {
+				t.Errorf("Moderate() answers = %q, reasons = %q, want %q, %q", answers, reasons, tt.wantAnswers, tt.wantReasons)
+			}
+			if q.Responses[0].RawAnswers[0] != q.Responses[0].Answers[0] {
+				t.Errorf("RawAnswers = %q, want the moderated answer", q.Responses[0].RawAnswers)
+			}
+			if len(q.Removed) != len(removed) {
+				t.Errorf("Removed = %d, want %d", len(q.Removed), len(removed))
+			}
+		})
+	}
+	q := newTestQuiz([]string{"A sweetener"}, []string{"Sugar"})
+	if _, err := q.Moderate(&Moderation{Blanks: map[string][]string{"7": {"x"}}}); err == nil {
+		t.Errorf("Moderate() with an unknown question did not fail")
+	}
+}
+
+func TestQuiz_WriteModerationReport(t *testing.T) {
+	q := newTestQuiz([]string{"A sweetener", "A color"},
+		[]string{"Darn Sugar", "darn red"},
+	)
+	removed, _ := q.Moderate(&Moderation{Blocklist: []string{"darn"}})
+	var b bytes.Buffer
+	if err := q.WriteModerationReport(&b, removed); err != nil {
+		t.Fatal(err)
+	}
+	report := b.String()
+	for _, want := range []string{"Question #1 -- A sweetener", "Question #2 -- A color", "a@acme.com", "Darn Sugar", "blocked: darn"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report is missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestReadBlocklist(t *testing.T) {
+	filename := writeTestFile(t, "blocklist.txt", "# words\ndarn\n\n  boss man  \n")
+	got, err := ReadBlocklist(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"darn", "boss man"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadBlocklist() = %q, want %q", got, want)
+	}
+}
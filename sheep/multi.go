@@ -0,0 +1,40 @@
+package sheep
+
+import "strings"
+
+// multiMarker starts the title of a question asking for several answers, such as
+// "📋 Name three breakfast cereals"
+const multiMarker = '📋'
+
+// splitEntries splits the answer to a multi-answer question on commas, semicolons, and
+// newlines
+func splitEntries(answer string) []string {
+	var entries []string
+	for _, e := range strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ';' || r == '\n' || r == '\r' }) {
+		if e = strings.TrimSpace(e); len(e) > 0 {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// AnswerEntries returns the entries of an answer which are counted and scored: the entries
+// of the answer to a multi-answer question, leaving out any which are the same as an entry
+// before them, or else the whole answer.  A blank answer has none.
+func (q *Quiz) AnswerEntries(idxQ int, answer string) []string {
+	if len(answer) == 0 {
+		return nil
+	}
+	if idxQ >= len(q.Questions) || !q.Questions[idxQ].Multi {
+		return []string{answer}
+	}
+	var entries []string
+	seen := make(map[string]bool)
+	for _, e := range splitEntries(answer) {
+		if key := q.AnswerKey(idxQ, e); !seen[key] {
+			seen[key] = true
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
@@ -0,0 +1,133 @@
+package sheep
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_splitEntries(t *testing.T) {
+	tests := []struct {
+		answer string
+		want   []string
+	}{
+		{"Cheerios", []string{"Cheerios"}},
+		{"Cheerios, Corn Flakes;Raisin Bran", []string{"Cheerios", "Corn Flakes", "Raisin Bran"}},
+		{"Cheerios\r\nCorn Flakes\n\n", []string{"Cheerios", "Corn Flakes"}},
+		{" , ;", nil},
+	}
+	for _, tt := range tests {
+		if got := splitEntries(tt.answer); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitEntries(%q) = %q, want %q", tt.answer, got, tt.want)
+		}
+	}
+}
+
+func TestQuiz_AnswerEntries(t *testing.T) {
+	q := newTestQuiz([]string{"A color", "📋 Three cereals"}, []string{"Red", "Cheerios"})
+	if got := q.AnswerEntries(0, "Red, Blue"); !reflect.DeepEqual(got, []string{"Red, Blue"}) {
+		t.Errorf("AnswerEntries(0) = %q", got)
+	}
+	if got := q.AnswerEntries(1, "Cheerios, cheerios!; Corn Flakes"); !reflect.DeepEqual(got, []string{"Cheerios", "Corn Flakes"}) {
+		t.Errorf("AnswerEntries(1) = %q", got)
+	}
+	if got := q.AnswerEntries(1, ""); got != nil {
+		t.Errorf("AnswerEntries(1, \"\") = %q", got)
+	}
+}
+
+func TestQuiz_CalcScores_multi(t *testing.T) {
+	q := newTestQuiz([]string{"📋 Three cereals", "×2 📋 Two Beatles"},
+		[]string{"Cheerios, Corn Flakes, Cheerios", "John; Paul"},
+		[]string{"cheerios\nRaisin Bran", "John"},
+		[]string{"", "Ringo, John"},
+	)
+	q.CalcScores()
+	pcs := q.Questions[0].PopulationCounts
+	if len(pcs) != 3 || pcs[q.AnswerKey(0, "Cheerios")].Freq != 2 || pcs[q.AnswerKey(0, "Corn Flakes")].Freq != 1 {
+		t.Errorf("counts = %v", pcs)
+	}
+	a, b, c := q.Responses[0], q.Responses[1], q.Responses[2]
+	// Cheerios counts once for the first player: 2 + 1
+	if a.AnswerScore[0] != 3 || b.AnswerScore[0] != 3 || c.AnswerScore[0] != 0 {
+		t.Errorf("cereal scores = %d, %d, %d", a.AnswerScore[0], b.AnswerScore[0], c.AnswerScore[0])
+	}
+	// John 3 + Paul 1, weighted ×2
+	if a.RawScore[1] != 4 || a.AnswerScore[1] != 8 || c.AnswerScore[1] != 8 || a.TotalScore != 11 {
+		t.Errorf("Beatles scores = %v, raw %v, total %d", a.AnswerScore, a.RawScore, a.TotalScore)
+	}
+}
+
+func TestQuiz_ApplyAliases_multi(t *testing.T) {
+	rules, err := ReadRules(writeTestFile(t, "quiz.rules.json", `{"questions": {"1": [{"find": "(?i)^none$", "replace": ""}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := newTestQuiz([]string{"📋 Three cereals"},
+		[]string{"Cheerios; Frosted Flakes"},
+		[]string{"Frosties,Cheerios, none"},
+		[]string{"Cheerios,Frosties"},
+	)
+	if err = q.SetRules(rules); err != nil {
+		t.Fatal(err)
+	}
+	a := &Aliases{}
+	a.Add("1", "Frosties", "Frosted Flakes")
+	changed, err := q.ApplyAliases(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range q.Responses {
+		got = append(got, r.Answers...)
+	}
+	want := []string{"Cheerios; Frosted Flakes", "Frosted Flakes, Cheerios", "Cheerios, Frosted Flakes"}
+	if changed != 2 || !reflect.DeepEqual(got, want) {
+		t.Errorf("ApplyAliases() = %d, %q, want 2, %q", changed, got, want)
+	}
+	raws, answers := q.NormalizedEntries(0, "Frosties,Cheerios, none")
+	if !reflect.DeepEqual(raws, []string{"Frosties", "Cheerios", "none"}) || !reflect.DeepEqual(answers, []string{"Frosted Flakes", "Cheerios", ""}) {
+		t.Errorf("NormalizedEntries() = %q, %q", raws, answers)
+	}
+	q.CalcScores()
+	if merged := q.MergedAnswers(0); !reflect.DeepEqual(merged, map[string][]string{"Frosted Flakes": {"Frosties"}}) {
+		t.Errorf("MergedAnswers() = %v", merged)
+	}
+}
+
+func TestQuiz_RuleEffects_multi(t *testing.T) {
+	q := newTestQuiz([]string{"📋 Two mountains"},
+		[]string{"Mt. Everest, Mt. Fuji"},
+		[]string{"Mt. Fuji; K2"},
+	)
+	if err := q.SetRules(&Rules{Questions: map[string][]Rule{"1": {{Find: `^Mt\. `, Replace: ""}}}}); err != nil {
+		t.Fatal(err)
+	}
+	effects := q.RuleEffects()
+	want := []RuleChange{{"Mt. Everest", "Everest", 1}, {"Mt. Fuji", "Fuji", 2}}
+	if len(effects) != 1 || !reflect.DeepEqual(effects[0].Changes, want) {
+		t.Errorf("RuleEffects() = %+v, want %+v", effects, want)
+	}
+}
+
+func TestQuiz_Moderate_multi(t *testing.T) {
+	q := newTestQuiz([]string{"📋 Two mountains"},
+		[]string{"Mt. Everest, Mt. Fuji"},
+		[]string{"Darn Hill; K2"},
+		[]string{"Darn Hill"},
+	)
+	q.Responses[0].RawAnswers = []string{"Mt. Everest, Mt. Fuji"}
+	removed, err := q.Moderate(&Moderation{Blocklist: []string{"darn"}, Blanks: map[string][]string{"1": {"mt. fuji"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var answers []string
+	for _, r := range q.Responses {
+		answers = append(answers, r.Answers[0])
+	}
+	if want := []string{"Mt. Everest", "K2", ""}; !reflect.DeepEqual(answers, want) || q.Responses[0].RawAnswers[0] != "Mt. Everest" {
+		t.Errorf("Moderate() answers = %q, raw %q, want %q", answers, q.Responses[0].RawAnswers, want)
+	}
+	if len(removed) != 3 || removed[0].Reason != "blanked (Mt. Fuji)" || removed[1].Replacement != "K2" {
+		t.Errorf("Moderate() removed = %+v", removed)
+	}
+}
@@ -0,0 +1,176 @@
+package sheep
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// numericMarker starts the title of a question whose answers are numbers, as 🎯 starts the
+// title of a bonus question: "🔢 A number between 1 and 10"
+const numericMarker = '🔢'
+
+var numberWords = map[string]float64{
+	"zero": 0, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7, "eight": 8, "nine": 9,
+	"ten": 10, "eleven": 11, "twelve": 12, "thirteen": 13, "fourteen": 14, "fifteen": 15, "sixteen": 16,
+	"seventeen": 17, "eighteen": 18, "nineteen": 19, "twenty": 20, "thirty": 30, "forty": 40, "fifty": 50,
+	"sixty": 60, "seventy": 70, "eighty": 80, "ninety": 90,
+}
+
+var scaleWords = map[string]float64{"hundred": 100, "thousand": 1e3, "million": 1e6, "billion": 1e9}
+
+// ordinalWords are the ordinals which are not the number word with "th" on the end
+var ordinalWords = map[string]string{
+	"first": "one", "second": "two", "third": "three", "fifth": "five", "eighth": "eight", "ninth": "nine", "twelfth": "twelve",
+}
+
+// ParseNumber reads a number written in digits, such as "1,000", "3.5", "7th", or "'69",
+// or in English words, such as "Seven.", "twenty-first", "a hundred and one", or "nineteen
+// sixty-nine".  A year written as "'69" is taken to be in the last hundred years.
+func ParseNumber(answer string) (float64, bool) {
+	s := strings.ToLower(strings.TrimSpace(answer))
+	s = strings.TrimRightFunc(s, func(r rune) bool { return unicode.IsPunct(r) && r != '%' })
+	s = strings.TrimPrefix(s, "#")
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return 0, false
+	}
+	if y, ok := parseShortYear(s); ok {
+		return y, true
+	}
+	if n, ok := parseDigits(s); ok {
+		return n, true
+	}
+	return parseNumberWords(s)
+}
+
+// parseShortYear reads a two digit year after an apostrophe
+func parseShortYear(s string) (float64, bool) {
+	for _, apostrophe := range []string{"'", "’", "‘"} {
+		if rest := strings.TrimPrefix(s, apostrophe); rest != s {
+			if len(rest) != 2 {
+				return 0, false
+			}
+			yy, err := strconv.Atoi(rest)
+			if err != nil {
+				return 0, false
+			}
+			now := time.Now().Year()
+			year := now - now%100 + yy
+			if year > now {
+				year -= 100
+			}
+			return float64(year), true
+		}
+	}
+	return 0, false
+}
+
+// parseDigits reads a number in digits with thousands separators or an ordinal suffix
+func parseDigits(s string) (float64, bool) {
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		if rest := strings.TrimSuffix(s, suffix); rest != s && len(rest) > 0 && unicode.IsDigit(rune(rest[len(rest)-1])) {
+			s = rest
+			break
+		}
+	}
+	return parseDecimal(s)
+}
+
+// parseDecimal reads a number of only digits with an optional sign, commas between groups
+// of three digits, and a decimal point.  It leaves out what strconv.ParseFloat also takes,
+// such as "inf", "NaN", and "1e3", and commas which are not thousands separators, as in
+// "1,5" or "1,2,3".
+func parseDecimal(s string) (float64, bool) {
+	whole, fraction, _ := strings.Cut(strings.TrimLeft(s, "+-"), ".")
+	if len(s)-len(strings.TrimLeft(s, "+-")) > 1 || len(whole)+len(fraction) == 0 || !allDigits(fraction) {
+		return 0, false
+	}
+	groups := strings.Split(whole, ",")
+	for i, g := range groups {
+		if !allDigits(g) || (len(groups) > 1 && (len(g) == 0 || len(g) > 3 || (i > 0 && len(g) != 3))) {
+			return 0, false
+		}
+	}
+	n, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64)
+	return n, err == nil
+}
+
+func allDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func parseNumberWords(s string) (float64, bool) {
+	words := strings.Fields(strings.ReplaceAll(s, "-", " "))
+	var total, current float64
+	seen := false
+	for i, w := range words {
+		if w == "and" || (w == "a" && i == 0) {
+			continue
+		}
+		w = cardinal(w)
+		if n, ok := numberWords[w]; ok {
+			switch tens := math.Mod(current, 100); {
+			case tens == 0:
+				current += n
+			case n >= 10 && current < 100:
+				current = current*100 + n // a year, as in "nineteen sixty"
+			case n < 10 && tens >= 20 && math.Mod(tens, 10) == 0:
+				current += n
+			default:
+				return 0, false
+			}
+		} else if scale, ok := scaleWords[w]; ok {
+			if current == 0 {
+				current = 1
+			}
+			if scale == 100 {
+				current *= scale
+			} else {
+				total += current * scale
+				current = 0
+			}
+		} else if n, ok := parseDecimal(w); ok && !seen {
+			current = n
+		} else {
+			return 0, false
+		}
+		seen = true
+	}
+	return total + current, seen
+}
+
+// cardinal turns an ordinal word into its number word: "twentieth" into "twenty"
+func cardinal(w string) string {
+	if c, ok := ordinalWords[w]; ok {
+		return c
+	}
+	if rest := strings.TrimSuffix(w, "ieth"); rest != w {
+		return rest + "y"
+	}
+	if rest := strings.TrimSuffix(w, "th"); rest != w {
+		if _, ok := numberWords[rest]; ok {
+			return rest
+		}
+		if _, ok := scaleWords[rest]; ok {
+			return rest
+		}
+	}
+	return w
+}
+
+// numericKey is the key of an answer to a numeric question, the number it reads as
+func numericKey(answer string) (string, bool) {
+	n, ok := ParseNumber(answer)
+	if !ok {
+		return "", false
+	}
+	return strconv.FormatFloat(n, 'f', -1, 64), true
+}
@@ -0,0 +1,118 @@
+package sheep
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseNumber(t *testing.T) {
+	thisYear := time.Now().Year()
+	tests := []struct {
+		answer string
+		want   float64
+		ok     bool
+	}{
+		{"7", 7, true},
+		{"seven", 7, true},
+		{"Seven.", 7, true},
+		{" 7th ", 7, true},
+		{"seventh", 7, true},
+		{"1969", 1969, true},
+		{"'69", 1969, true},
+		{"’05", 2005, true},
+		{"1,000", 1000, true},
+		{"one thousand", 1000, true},
+		{"a hundred and one", 101, true},
+		{"twenty-one", 21, true},
+		{"Twenty First!", 21, true},
+		{"twentieth", 20, true},
+		{"3 million", 3e6, true},
+		{"nineteen sixty-nine", 1969, true},
+		{"seven three", 0, false},
+		{"3.5", 3.5, true},
+		{"#7", 7, true},
+		{"-5", -5, true},
+		{"1st", 1, true},
+		{"Second", 2, true},
+		{"a few", 0, false},
+		{"seven dwarfs", 0, false},
+		{"nan", 0, false},
+		{"Inf", 0, false},
+		{"-Infinity", 0, false},
+		{"1e3", 0, false},
+		{"0x10", 0, false},
+		{"1.2.3", 0, false},
+		{"1,5", 0, false},
+		{"1,2,3", 0, false},
+		{"12,345,678.5", 12345678.5, true},
+		{"1234,567", 0, false},
+		{",123", 0, false},
+		{"1,000.5,0", 0, false},
+		{"+-5", 0, false},
+		{"5.", 5, true},
+		{"+.5", 0.5, true},
+		{"nan thousand", 0, false},
+		{"'1969", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.answer, func(t *testing.T) {
+			got, ok := ParseNumber(tt.answer)
+			if ok != tt.ok || (ok && got != tt.want) {
+				t.Errorf("ParseNumber(%q) = %v, %v, want %v, %v", tt.answer, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+	if y, _ := ParseNumber("'" + time.Now().Format("06")); int(y) != thisYear {
+		t.Errorf("this year = %v, want %d", y, thisYear)
+	}
+}
+
+func Test_buildQuestion_markers(t *testing.T) {
+	tests := []struct {
+		title   string
+		numeric bool
+		bonus   string
+	}{
+		{"A number between 1 and 10", false, ""},
+		{"🔢 A number between 1 and 10", true, ""},
+		{"🔢🎯 A famous year [1969]", true, "1969"},
+		{"🎯 🔢 A famous year [1969]", true, "1969"},
+		{"🎯 A fruit [Kiwi]", false, "Kiwi"},
+		{"A year like 🔢", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			q, err := buildQuestion(tt.title)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if q.Numeric != tt.numeric || strings.Join(q.BonusAnswers, "|") != tt.bonus || q.BonusQuestion != (tt.bonus != "") {
+				t.Errorf("buildQuestion() = %+v", q)
+			}
+		})
+	}
+}
+
+func TestQuiz_CalcScores_numeric(t *testing.T) {
+	q := newTestQuiz([]string{"🔢 A number between 1 and 10", "🔢🎯 A famous year [1969]", "A number word"},
+		[]string{"7", "1969", "seven"},
+		[]string{"seven", "'69", "Seven."},
+		[]string{"Seven.", "1776", "7"},
+		[]string{"3", "Nineteen sixty-nine", "3"},
+	)
+	q.CalcScores()
+	if pc := q.Questions[0].PopulationCounts["7"]; pc == nil || pc.Freq != 3 || pc.OriginalAnswer != "7" {
+		t.Errorf("question 1 PopulationCounts = %v", q.Questions[0].PopulationCounts)
+	}
+	if pc := q.Questions[1].PopulationCounts["1969"]; pc == nil || pc.Freq != 3 || pc.Bonus != 4 {
+		t.Errorf("question 2 PopulationCounts = %v", q.Questions[1].PopulationCounts)
+	}
+	if len(q.Questions[2].PopulationCounts) != 3 {
+		t.Errorf("untagged question PopulationCounts = %v", q.Questions[2].PopulationCounts)
+	}
+	if got := q.Responses[1].AnswerScore; got[0] != 3 || got[1] != 4 {
+		t.Errorf("AnswerScore = %v", got)
+	}
+}
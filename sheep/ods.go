@@ -0,0 +1,243 @@
+package sheep
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// OpenDocument namespaces used in content.xml
+const (
+	odfOffice = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	odfTable  = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	odfText   = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+)
+
+var odsMimeType = []byte("application/vnd.oasis.opendocument.spreadsheet")
+
+// isODS reports whether the file is an OpenDocument spreadsheet.  The mimetype entry
+// must be stored first and uncompressed, so its contents are near the start of the file.
+func isODS(head []byte) bool {
+	return isZip(head) && bytes.Contains(head, odsMimeType)
+}
+
+// odsReader reads an OpenDocument spreadsheet, such as one saved by LibreOffice Calc
+type odsReader struct{}
+
+func (odsReader) Name() string { return "ODS" }
+
+func (odsReader) Claim(filename string, head []byte) bool {
+	return isODS(head)
+}
+
+func (odsReader) Read(filename string, opts ReadOptions) ([]Question, []Response, error) {
+	return readODS(filename, opts)
+}
+
+// odsTable is one sheet of an OpenDocument spreadsheet
+type odsTable struct {
+	name string
+	rows [][]string
+}
+
+// Read responses from an OpenDocument spreadsheet.  The sheets are chosen the same way as
+// for an XLSX workbook.
+func readODS(filename string, opts ReadOptions) ([]Question, []Response, error) {
+	z, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer z.Close()
+	var content *zip.File
+	for _, f := range z.File {
+		if f.Name == "content.xml" {
+			content = f
+			break
+		}
+	}
+	if content == nil {
+		return nil, nil, fmt.Errorf("%s has no content.xml, it is not an OpenDocument spreadsheet", filename)
+	}
+	r, err := content.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer r.Close()
+	tables, err := parseODSContent(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %s", filename, err)
+	}
+	sheets := make([]string, 0, len(tables))
+	for _, t := range tables {
+		sheets = append(sheets, t.name)
+	}
+	return ReadSheets(sheets, opts, func(sheet string) ([][]string, error) {
+		for _, t := range tables {
+			if t.name == sheet {
+				return t.rows, nil
+			}
+		}
+		return nil, fmt.Errorf("there is no sheet named '%s'", sheet)
+	})
+}
+
+// parseODSContent reads the cell values of every table in content.xml.  Repeated rows and
+// cells are expanded, except for the empty ones at the end of a row or table, which
+// spreadsheets repeat out to the largest sheet size.
+func parseODSContent(r io.Reader) ([]odsTable, error) {
+	var (
+		tables    []odsTable
+		table     *odsTable
+		row       []string
+		inRow     bool
+		rowRepeat int
+		emptyRows int // empty rows not yet added, in case a non-empty row follows
+		cell      *strings.Builder
+		cellValue string
+		cellSet   bool // cellValue came from an attribute rather than the text
+		repeat    int
+		emptyCols int // empty cells not yet added, in case a non-empty cell follows
+		textDepth int // depth of text:p elements, which hold the cell text
+		paras     int
+	)
+	d := xml.NewDecoder(r)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space == odfOffice && t.Name.Local == "annotation" {
+				// Comments on a cell are not part of its value
+				if err = d.Skip(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			switch t.Name.Space {
+			case odfTable:
+				switch t.Name.Local {
+				case "table":
+					tables = append(tables, odsTable{name: odsAttr(t, odfTable, "name")})
+					table = &tables[len(tables)-1]
+					emptyRows = 0
+				case "table-row":
+					inRow, row, emptyCols = true, nil, 0
+					rowRepeat = odsRepeat(t, "number-rows-repeated")
+				case "table-cell", "covered-table-cell":
+					if !inRow {
+						continue
+					}
+					cell, cellValue, cellSet, paras = &strings.Builder{}, "", false, 0
+					repeat = odsRepeat(t, "number-columns-repeated")
+					switch odsAttr(t, odfOffice, "value-type") {
+					case "float", "percentage", "currency":
+						cellValue, cellSet = odsAttr(t, odfOffice, "value"), true
+					case "date":
+						cellValue, cellSet = odsAttr(t, odfOffice, "date-value"), true
+					case "time":
+						cellValue, cellSet = odsAttr(t, odfOffice, "time-value"), true
+					case "boolean":
+						cellValue, cellSet = odsAttr(t, odfOffice, "boolean-value"), true
+					}
+				}
+			case odfText:
+				if cell == nil {
+					continue
+				}
+				switch t.Name.Local {
+				case "p":
+					if paras > 0 {
+						cell.WriteByte('\n')
+					}
+					paras++
+					textDepth++
+				case "s":
+					n, _ := strconv.Atoi(odsAttr(t, odfText, "c"))
+					if n < 1 {
+						n = 1
+					}
+					cell.WriteString(strings.Repeat(" ", n))
+				case "tab":
+					cell.WriteByte('\t')
+				case "line-break":
+					cell.WriteByte('\n')
+				}
+			}
+		case xml.CharData:
+			if cell != nil && textDepth > 0 {
+				cell.Write(t)
+			}
+		case xml.EndElement:
+			switch {
+			case t.Name.Space == odfText && t.Name.Local == "p" && cell != nil:
+				textDepth--
+			case t.Name.Space == odfTable && (t.Name.Local == "table-cell" || t.Name.Local == "covered-table-cell"):
+				if cell == nil {
+					continue
+				}
+				value := cell.String()
+				if cellSet {
+					value = cellValue
+				}
+				if len(value) == 0 {
+					emptyCols += repeat
+				} else {
+					for ; emptyCols > 0; emptyCols-- {
+						row = append(row, "")
+					}
+					for i := 0; i < repeat; i++ {
+						row = append(row, value)
+					}
+				}
+				cell = nil
+			case t.Name.Space == odfTable && t.Name.Local == "table-row":
+				if table == nil {
+					continue
+				}
+				if len(row) == 0 {
+					emptyRows += rowRepeat
+				} else {
+					for ; emptyRows > 0; emptyRows-- {
+						table.rows = append(table.rows, []string{})
+					}
+					for i := 0; i < rowRepeat; i++ {
+						table.rows = append(table.rows, row)
+					}
+				}
+				inRow = false
+			case t.Name.Space == odfTable && t.Name.Local == "table":
+				table = nil
+			}
+		}
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("spreadsheet has no sheets")
+	}
+	return tables, nil
+}
+
+func odsAttr(t xml.StartElement, space, local string) string {
+	for _, a := range t.Attr {
+		if a.Name.Space == space && a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func odsRepeat(t xml.StartElement, attr string) int {
+	n, err := strconv.Atoi(odsAttr(t, odfTable, attr))
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
@@ -0,0 +1,128 @@
+package sheep
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadODS(t *testing.T) {
+	tests := []struct {
+		name      string
+		filename  string
+		sheet     string
+		wantErr   string
+		questions []string
+		answers   map[string][]string // answers by email
+		completed map[string]time.Time
+	}{
+		{
+			name:      "google forms",
+			filename:  "testdata/google-forms.ods",
+			questions: []string{"A sweetener", "🎯 A color", "A famous George"},
+			answers: map[string][]string{
+				"bhf@acme.com": {"Sugar", "Blue", "George Clooney"},
+				"abc@acme.com": {"Stevia", "Stevia", ""},
+			},
+			completed: map[string]time.Time{"bhf@acme.com": time.Date(2024, 3, 15, 10, 1, 0, 0, time.UTC)},
+		},
+		{
+			name:      "microsoft forms on a renamed sheet",
+			filename:  "testdata/microsoft-forms.ods",
+			questions: []string{"A sweetener", "A number between 1 and 10"},
+			answers:   map[string][]string{"mrb@acme.com": {"Honey", "3"}},
+			completed: map[string]time.Time{"jjc@acme.com": time.Date(2024, 3, 15, 10, 4, 48, 0, time.UTC)},
+		},
+		{
+			name:      "named sheet",
+			filename:  "testdata/rounds.ods",
+			sheet:     "Round 2",
+			questions: []string{"A color"},
+			answers:   map[string][]string{"c@acme.com": {"Green"}},
+		},
+		{
+			name:      "every sheet is a round",
+			filename:  "testdata/rounds.ods",
+			sheet:     AllSheets,
+			questions: []string{"A sweetener", "A color"},
+			answers: map[string][]string{
+				"a@acme.com": {"Sugar", ""},
+				"b@acme.com": {"Honey", "Red"},
+				"c@acme.com": {"", "Green"},
+			},
+		},
+		{name: "missing sheet", filename: "testdata/rounds.ods", sheet: "Round 3", wantErr: "sheets found: Round 1, Round 2"},
+		{name: "no sheet matches", filename: "testdata/microsoft-forms.ods", sheet: "Notes", wantErr: "do not match"},
+		{name: "no content", filename: "testdata/no-content.ods", wantErr: "no content.xml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			questions, responses, err := readODS(tt.filename, ReadOptions{Sheet: tt.sheet})
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readODS() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readODS() error = %v", err)
+			}
+			texts := make([]string, len(questions))
+			for i := range questions {
+				texts[i] = questions[i].Text
+			}
+			if !reflect.DeepEqual(texts, tt.questions) {
+				t.Errorf("questions = %q, want %q", texts, tt.questions)
+			}
+			byEmail := make(map[string]Response)
+			for _, r := range responses {
+				byEmail[r.Email] = r
+				if len(r.AnswerScore) != len(questions) {
+					t.Errorf("%s has %d answer scores, want %d", r.Email, len(r.AnswerScore), len(questions))
+				}
+			}
+			for email, want := range tt.answers {
+				if got := byEmail[email].Answers; !reflect.DeepEqual(got, want) {
+					t.Errorf("%s answers = %q, want %q", email, got, want)
+				}
+			}
+			for email, want := range tt.completed {
+				if got := byEmail[email].Completed.Round(time.Second); !got.Equal(want) {
+					t.Errorf("%s completed = %v, want %v", email, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReadODS_quiz(t *testing.T) {
+	var q Quiz
+	if err := q.ReadResponses("testdata/google-forms.ods", ReadOptions{}); err != nil {
+		t.Fatalf("ReadResponses() error = %v", err)
+	}
+	q.EliminateDups()
+	q.CalcScores()
+	// jjc's second response replaces the first, so Blue earns the bonus for two players
+	if len(q.Responses) != 3 || q.Questions[1].BonusValue != 3 {
+		t.Errorf("responses = %d bonus = %d, want 3 and 3", len(q.Responses), q.Questions[1].BonusValue)
+	}
+}
+
+func TestParseODSContent_trailingCells(t *testing.T) {
+	content := `<office:document-content xmlns:office="` + odfOffice + `" xmlns:table="` + odfTable + `" xmlns:text="` + odfText + `">
+<office:body><office:spreadsheet><table:table table:name="S">
+<table:table-row><table:table-cell><text:p>a</text:p></table:table-cell><table:table-cell table:number-columns-repeated="2"/><table:table-cell><text:p>b</text:p><text:p>c</text:p></table:table-cell><table:table-cell table:number-columns-repeated="16000"/></table:table-row>
+<table:table-row table:number-rows-repeated="3"><table:table-cell/></table:table-row>
+<table:table-row table:number-rows-repeated="2"><table:table-cell><text:p>x<text:tab/>y</text:p></table:table-cell></table:table-row>
+<table:table-row table:number-rows-repeated="1000000"><table:table-cell table:number-columns-repeated="16000"/></table:table-row>
+</table:table></office:spreadsheet></office:body></office:document-content>`
+	tables, err := parseODSContent(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"a", "", "", "b\nc"}, {}, {}, {}, {"x\ty"}, {"x\ty"}}
+	if len(tables) != 1 || !reflect.DeepEqual(tables[0].rows, want) {
+		t.Errorf("rows = %q, want %q", tables[0].rows, want)
+	}
+}
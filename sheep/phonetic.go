@@ -0,0 +1,66 @@
+package sheep
+
+import "strings"
+
+// Confidence of a merge made only because two answers sound alike
+const (
+	phoneticConfidence          = 0.8 // the primary codes match
+	phoneticAlternateConfidence = 0.7 // the codes match only through an alternate code
+)
+
+// PhoneticKeys returns the primary and alternate phonetic keys of an answer, the Double
+// Metaphone codes of each of its words after the answer is canonicalized.  Words with no
+// code, such as numbers, are kept as they are.
+func (c *Canonicalizer) PhoneticKeys(answer string) (primary, alternate string) {
+	words := strings.Fields(c.Key(answer))
+	p := make([]string, 0, len(words))
+	a := make([]string, 0, len(words))
+	for _, w := range words {
+		wp, wa := DoubleMetaphone(w)
+		if len(wp) == 0 && len(wa) == 0 {
+			wp, wa = w, w
+		}
+		p = append(p, wp)
+		a = append(a, wa)
+	}
+	return strings.Join(p, " "), strings.Join(a, " ")
+}
+
+// SoundAlike scores two answers which sound the same by their phonetic keys, or returns 0
+// if they do not
+func (c *Canonicalizer) SoundAlike(a, b string) float64 {
+	ap, aa := c.PhoneticKeys(a)
+	bp, ba := c.PhoneticKeys(b)
+	switch {
+	case len(ap) == 0 || len(bp) == 0:
+		return 0
+	case ap == bp:
+		return phoneticConfidence
+	case ap == ba || aa == bp || aa == ba:
+		return phoneticAlternateConfidence
+	}
+	return 0
+}
+
+// MergeSoundAlike merges the answers which sound alike in the questions given by index,
+// adding them to a copy of the aliases and applying it to the quiz.  The merges are
+// returned so they can be reviewed; the aliases themselves are not changed.
+func (q *Quiz) MergeSoundAlike(aliases *Aliases, questions []int) ([]MergeGroup, error) {
+	q.CalcScores()
+	merged := aliases.Clone()
+	var groups []MergeGroup
+	for _, idxQ := range questions {
+		key := merged.QuestionKey(q, idxQ)
+		for _, g := range q.Questions[idxQ].suggestMerges(q.canon(), SuggestOptions{SoundOnly: true}) {
+			g.Question = idxQ
+			for _, v := range g.Variants() {
+				merged.Add(key, v, g.Canonical())
+			}
+			groups = append(groups, g)
+		}
+	}
+	if _, err := q.ApplyAliases(merged); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
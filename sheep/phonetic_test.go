@@ -0,0 +1,118 @@
+package sheep
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDoubleMetaphone(t *testing.T) {
+	tests := []struct {
+		word, primary, alternate string
+	}{
+		{"Smith", "SM0", "XMT"},
+		{"Schmidt", "XMT", "SMT"},
+		{"George", "JRJ", "KRK"},
+		{"Jorge", "JRJ", "ARK"},
+		{"Katherine", "K0RN", "KTRN"},
+		{"Catherine", "K0RN", "KTRN"},
+		{"Jon", "JN", "AN"},
+		{"John", "JN", "AN"},
+		{"Stephen", "STFN", "STFN"},
+		{"Caesar", "SSR", "SSR"},
+		{"Michael", "MKL", "MXL"},
+		{"Xavier", "SF", "SFR"},
+		{"Thumb", "0M", "TM"},
+		{"Jose", "HS", "HS"},
+		{"Knight", "NT", "NT"},
+		{"laugh", "LF", "LF"},
+		{"Zhang", "JNK", "JNK"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			p, a := DoubleMetaphone(tt.word)
+			if p != tt.primary || a != tt.alternate {
+				t.Errorf("DoubleMetaphone(%s) = %s, %s, want %s, %s", tt.word, p, a, tt.primary, tt.alternate)
+			}
+		})
+	}
+}
+
+func TestSoundAlike(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"George Clooney", "Jorge Cluny", phoneticConfidence},
+		{"Catherine Zeta-Jones", "Katherine Zeta Jones", phoneticConfidence},
+		{"Schmidt", "Smith", phoneticAlternateConfidence},
+		{"George", "Gorge", phoneticAlternateConfidence},
+		{"George Clooney", "George Washington", 0},
+		{"Apollo 11", "Apollo 13", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := DefaultCanonicalizer.SoundAlike(tt.a, tt.b); got != tt.want {
+				t.Errorf("SoundAlike() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuiz_MergeSoundAlike(t *testing.T) {
+	q := newTestQuiz([]string{"Someone named George", "A color"},
+		[]string{"George Clooney", "Red"},
+		[]string{"George Clooney", "Rod"},
+		[]string{"Jorge Cluny", "Red"},
+		[]string{"George Lucas", "Read"},
+	)
+	aliases := &Aliases{}
+	groups, err := q.MergeSoundAlike(aliases, []int{0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 || groups[0].Canonical() != "George Clooney" || !reflect.DeepEqual(groups[0].Variants(), []string{"Jorge Cluny"}) {
+		t.Fatalf("MergeSoundAlike() = %+v", groups)
+	}
+	if len(aliases.Questions) != 0 {
+		t.Errorf("aliases were changed: %v", aliases.Questions)
+	}
+	q.CalcScores()
+	if q.Responses[2].Answers[0] != "George Clooney" || q.Responses[2].AnswerScore[0] != 3 || q.Responses[1].Answers[1] != "Rod" {
+		t.Errorf("response = %+v", q.Responses[2])
+	}
+
+	q.CalcScores()
+	groups = q.SuggestMerges(SuggestOptions{Threshold: 0.9, Phonetic: true})
+	if len(groups) != 1 || groups[0].Question != 1 || !groups[0].Phonetic || len(groups[0].Answers) != 3 {
+		t.Errorf("SuggestMerges() = %+v", groups)
+	}
+}
+
+func TestQuiz_MergeSoundAlike_canon(t *testing.T) {
+	tests := []struct {
+		name  string
+		canon *Canonicalizer
+		want  int
+	}{
+		{"default", nil, 1},
+		{"keep articles", NewCanonicalizer(NormPunctuation, NormWhitespace), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newTestQuiz([]string{"A band", "A mission"},
+				[]string{"The Beatles", "Apollo 11"},
+				[]string{"Beetles", "Apollo 13"},
+			)
+			q.Canon = tt.canon
+			groups, err := q.MergeSoundAlike(&Aliases{}, []int{0, 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Apollo 11 and 13 are similar but do not sound alike, so they are never merged
+			if len(groups) != tt.want {
+				t.Errorf("MergeSoundAlike() = %+v, want %d groups", groups, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package sheep
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// DefaultPinkCowPenalty is the number of points the player holding the pink cow at the end
+// of a quiz loses
+const DefaultPinkCowPenalty = 5
+
+// PinkCow is who holds the pink cow and where they earned it.  A zero PinkCow is held by
+// nobody.
+type PinkCow struct {
+	Email    string `json:"email,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Quiz     string `json:"quiz,omitempty"`     // quiz the cow was earned in
+	Question int    `json:"question,omitempty"` // number of the question it was earned on
+	Answer   string `json:"answer,omitempty"`   // the answer nobody else gave
+}
+
+// Held reports whether anyone holds the pink cow
+func (c PinkCow) Held() bool {
+	return len(c.Email) > 0
+}
+
+// PinkCowGame plays a quiz with the pink cow.  On each question, in order, a player who is
+// the only one to give an answer which nobody else gave earns the cow, unless more than
+// one answer was given by only one player.  CalcScores sets After to who holds the cow at
+// the end of the quiz, and if they played, they lose Penalty points.
+type PinkCowGame struct {
+	Quiz    string // name of the quiz, recorded with the cow when it is earned
+	Penalty int
+	Before  PinkCow // who held the cow coming into the quiz
+	After   PinkCow
+}
+
+// PinkCowState is the file which carries the pink cow from quiz to quiz.  The holder
+// before the last quiz is kept too, so the last quiz can be scored again.
+type PinkCowState struct {
+	Holder PinkCow `json:"holder"`
+	Quiz   string  `json:"quiz,omitempty"` // last quiz played with the cow
+	Before PinkCow `json:"before"`
+}
+
+// ReadPinkCowState reads the pink cow state file.  A file which does not exist means
+// nobody holds the cow.
+func ReadPinkCowState(filename string) (*PinkCowState, error) {
+	s := &PinkCowState{}
+	if err := readJSONFile(filename, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Save writes the pink cow state file
+func (s *PinkCowState) Save(filename string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(b, '\n'), 0644)
+}
+
+// Game starts playing a quiz with the pink cow.  Scoring the last quiz again starts from
+// who held the cow before it.
+func (s *PinkCowState) Game(quiz string, penalty int) *PinkCowGame {
+	before := s.Holder
+	if len(s.Quiz) > 0 && s.Quiz == quiz {
+		before = s.Before
+	}
+	return &PinkCowGame{Quiz: quiz, Penalty: penalty, Before: before, After: before}
+}
+
+// Record keeps who holds the cow after a game
+func (s *PinkCowState) Record(g *PinkCowGame) {
+	s.Holder, s.Quiz, s.Before = g.After, g.Quiz, g.Before
+}
+
+// passPinkCow finds who holds the pink cow after each question and takes the penalty from
+// the holder at the end.  The scores must already be calculated.
+func (q *Quiz) passPinkCow() {
+	g := q.PinkCow
+	g.After = g.Before
+	for idxQ := range q.Questions {
+		single, singles := "", 0
+		for key, pc := range q.Questions[idxQ].PopulationCounts {
+			if pc.Freq == 1 && pc.Bonus == 0 {
+				single = key
+				singles++
+			}
+		}
+		if singles != 1 {
+			continue
+		}
+	responders:
+		for _, r := range q.Responses {
+			if idxQ >= len(r.Answers) {
+				continue
+			}
+			for _, entry := range q.AnswerEntries(idxQ, r.Answers[idxQ]) {
+				if q.AnswerKey(idxQ, entry) == single {
+					g.After = PinkCow{Email: r.Email, Name: r.Name, Quiz: g.Quiz, Question: idxQ + 1, Answer: entry}
+					break responders
+				}
+			}
+		}
+	}
+	for idxR := range q.Responses {
+		if g.After.Held() && q.Responses[idxR].Email == g.After.Email {
+			q.Responses[idxR].TotalScore -= g.Penalty
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package sheep
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestQuiz_passPinkCow(t *testing.T) {
+	lisa := PinkCow{Email: "lisa@acme.com", Name: "Lisa", Quiz: "week1.xlsx", Question: 2, Answer: "Plaid"}
+	tests := []struct {
+		name      string
+		answers   [][]string
+		before    PinkCow
+		wantEmail string
+		wantQ     int
+		wantTotal []int
+	}{
+		{"earned on the last question with one unique answer",
+			[][]string{{"Red", "Apple"}, {"Red", "Apple"}, {"Blue", "Pear"}},
+			PinkCow{}, "c@acme.com", 2, []int{4, 4, -3}},
+		{"two unique answers pass nothing",
+			[][]string{{"Red", "Kiwi"}, {"Blue", "Kiwi"}, {"Green", "Kiwi"}},
+			lisa, "lisa@acme.com", 2, []int{4, 4, 4}},
+		{"the holder who played is penalized",
+			[][]string{{"Red", "Apple"}, {"Red", "Apple"}, {"Red", "Apple"}},
+			PinkCow{Email: "b@acme.com", Name: "B"}, "b@acme.com", 0, []int{6, 1, 6}},
+		{"earned on an earlier question is kept",
+			[][]string{{"Red", "Apple"}, {"Red", "Pear"}, {"Blue", "Kiwi"}},
+			PinkCow{}, "c@acme.com", 1, []int{3, 3, -3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newTestQuiz([]string{"A color", "A fruit"}, tt.answers...)
+			q.PinkCow = &PinkCowGame{Quiz: "week2.xlsx", Penalty: DefaultPinkCowPenalty, Before: tt.before}
+			q.CalcScores()
+			got := q.PinkCow.After
+			if got.Email != tt.wantEmail || got.Question != tt.wantQ {
+				t.Errorf("After = %+v, want %s on question %d", got, tt.wantEmail, tt.wantQ)
+			}
+			var totals []int
+			for _, r := range q.Responses {
+				totals = append(totals, r.TotalScore)
+			}
+			if !reflect.DeepEqual(totals, tt.wantTotal) {
+				t.Errorf("totals = %v, want %v", totals, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestPinkCowState(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "pinkcow.json")
+	s, err := ReadPinkCowState(filename)
+	if err != nil || s.Holder.Held() {
+		t.Fatalf("ReadPinkCowState() of a missing file = %+v, %v", s, err)
+	}
+	g := s.Game("week1.xlsx", 5)
+	g.After = PinkCow{Email: "a@acme.com", Name: "A", Quiz: "week1.xlsx", Question: 3, Answer: "Plaid"}
+	s.Record(g)
+	if err = s.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+	if s, err = ReadPinkCowState(filename); err != nil {
+		t.Fatal(err)
+	}
+	if again := s.Game("week1.xlsx", 5); again.Before.Held() {
+		t.Errorf("scoring week1 again starts with %+v, want nobody", again.Before)
+	}
+	if next := s.Game("week2.xlsx", 5); next.Before.Email != "a@acme.com" || next.Before.Answer != "Plaid" {
+		t.Errorf("week2 starts with %+v, want a@acme.com", next.Before)
+	}
+}
@@ -0,0 +1,363 @@
+package sheep
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Profile describes the layout of a response export: which columns hold the time a
+// response was completed, the player's email and name, and the answers.  Profiles are
+// JSON files; see the profiles directory for the built-in ones.
+type Profile struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Timestamp   ColumnSpec   `json:"timestamp"`
+	TimeLayouts []string     `json:"timeLayouts,omitempty"` // Go time layouts to parse the timestamp with
+	Email       ColumnSpec   `json:"email"`
+	PlayerName  ColumnSpec   `json:"playerName"`
+	Questions   QuestionSpec `json:"questions"`
+}
+
+// ColumnSpec picks a column by its header text, a regular expression matched against
+// the header, or its column number (1 is the first column).  The first column which
+// matches is used.
+type ColumnSpec struct {
+	Header   string `json:"header,omitempty"` // compared ignoring case and surrounding spaces
+	Regex    string `json:"regex,omitempty"`
+	Index    int    `json:"index,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+	re       *regexp.Regexp
+}
+
+// QuestionSpec picks the question columns.  When Columns is empty, every column after the
+// After column which is not the timestamp, email, or name column and whose header does not
+// match one of the Skip expressions is a question.
+type QuestionSpec struct {
+	Columns     []ColumnSpec `json:"columns,omitempty"`
+	After       *ColumnSpec  `json:"after,omitempty"`
+	Skip        []string     `json:"skip,omitempty"`
+	TrimNumbers bool         `json:"trimNumbers,omitempty"` // remove "1. " from the start of question titles
+	skip        []*regexp.Regexp
+}
+
+// columnLayout is where a profile found its columns in a header row
+type columnLayout struct {
+	profile   *Profile
+	timestamp int // -1 when there is no timestamp column
+	email     int // -1 when there is no email column
+	name      int // -1 when there is no name column
+	questions []int
+}
+
+//go:embed profiles/*.json
+var profileFS embed.FS
+
+var builtinProfiles []*Profile
+
+func init() {
+	for _, name := range []string{"microsoft-forms", "google-forms"} {
+		b, err := profileFS.ReadFile(path.Join("profiles", name+".json"))
+		if err != nil {
+			panic(err)
+		}
+		p, err := ParseProfile(b)
+		if err != nil {
+			panic(fmt.Sprintf("built-in profile %s: %s", name, err))
+		}
+		builtinProfiles = append(builtinProfiles, p)
+	}
+}
+
+// BuiltinProfiles returns the profiles tried, in order, when no profile is given
+func BuiltinProfiles() []*Profile {
+	return builtinProfiles
+}
+
+// LoadProfile returns the built-in profile with the name or else reads a profile file
+func LoadProfile(nameOrFile string) (*Profile, error) {
+	for _, p := range builtinProfiles {
+		if p.Name == nameOrFile {
+			return p, nil
+		}
+	}
+	b, err := ioutil.ReadFile(nameOrFile)
+	if err != nil {
+		return nil, err
+	}
+	p, err := ParseProfile(b)
+	if err != nil {
+		return nil, fmt.Errorf("profile %s: %s", nameOrFile, err)
+	}
+	return p, nil
+}
+
+// ParseProfile parses and checks a JSON profile
+func ParseProfile(b []byte) (*Profile, error) {
+	p := &Profile{}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, err
+	}
+	if err := p.compile(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Profile) compile() error {
+	specs := map[string]*ColumnSpec{"timestamp": &p.Timestamp, "email": &p.Email, "name": &p.PlayerName}
+	if p.Questions.After != nil {
+		specs["questions.after"] = p.Questions.After
+	}
+	for i := range p.Questions.Columns {
+		specs[fmt.Sprintf("questions.columns[%d]", i)] = &p.Questions.Columns[i]
+	}
+	for field, spec := range specs {
+		if err := spec.compile(); err != nil {
+			return fmt.Errorf("%s: %s", field, err)
+		}
+	}
+	p.Questions.skip = p.Questions.skip[:0]
+	for _, s := range p.Questions.Skip {
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return fmt.Errorf("questions.skip: %s", err)
+		}
+		p.Questions.skip = append(p.Questions.skip, re)
+	}
+	return nil
+}
+
+func (c *ColumnSpec) compile() error {
+	if len(c.Regex) > 0 {
+		re, err := regexp.Compile(c.Regex)
+		if err != nil {
+			return err
+		}
+		c.re = re
+	}
+	return nil
+}
+
+func (c *ColumnSpec) empty() bool {
+	return len(c.Header) == 0 && len(c.Regex) == 0 && c.Index == 0
+}
+
+func (c *ColumnSpec) String() string {
+	switch {
+	case len(c.Header) > 0:
+		return fmt.Sprintf("'%s'", c.Header)
+	case len(c.Regex) > 0:
+		return fmt.Sprintf("matching /%s/", c.Regex)
+	}
+	return fmt.Sprintf("#%d", c.Index)
+}
+
+// find returns the index of the first column in the header row which matches, or -1
+func (c *ColumnSpec) find(row []string) int {
+	if c.Index > 0 {
+		if c.Index <= len(row) {
+			return c.Index - 1
+		}
+		return -1
+	}
+	for i, title := range row {
+		title = strings.TrimSpace(title)
+		if len(c.Header) > 0 && strings.EqualFold(title, c.Header) {
+			return i
+		}
+		if c.re != nil && c.re.MatchString(title) {
+			return i
+		}
+	}
+	return -1
+}
+
+// findRequired finds the column, which is an error if the column is required and missing
+func (c *ColumnSpec) findRequired(row []string, field string) (int, error) {
+	if c.empty() {
+		return -1, nil
+	}
+	idx := c.find(row)
+	if idx < 0 && !c.Optional {
+		return -1, fmt.Errorf("no %s column %s", field, c)
+	}
+	return idx, nil
+}
+
+// match finds the profile's columns in the header row and builds the questions
+func (p *Profile) match(row []string) (*columnLayout, []Question, error) {
+	var (
+		l   = &columnLayout{profile: p}
+		err error
+	)
+	if l.timestamp, err = p.Timestamp.findRequired(row, "timestamp"); err != nil {
+		return nil, nil, err
+	}
+	if l.email, err = p.Email.findRequired(row, "email"); err != nil {
+		return nil, nil, err
+	}
+	if l.name, err = p.PlayerName.findRequired(row, "name"); err != nil {
+		return nil, nil, err
+	}
+	if l.email < 0 && l.name < 0 {
+		return nil, nil, fmt.Errorf("profile must find an email or a name column")
+	}
+	if len(p.Questions.Columns) > 0 {
+		for i := range p.Questions.Columns {
+			idx := p.Questions.Columns[i].find(row)
+			if idx < 0 {
+				return nil, nil, fmt.Errorf("no question column %s", &p.Questions.Columns[i])
+			}
+			l.questions = append(l.questions, idx)
+		}
+	} else {
+		start := 0
+		if p.Questions.After != nil {
+			if start = p.Questions.After.find(row); start < 0 {
+				return nil, nil, fmt.Errorf("no column %s for questions to follow", p.Questions.After)
+			}
+			start++
+		}
+	columns:
+		for i := start; i < len(row); i++ {
+			if i == l.timestamp || i == l.email || i == l.name {
+				continue
+			}
+			for _, re := range p.Questions.skip {
+				if re.MatchString(strings.TrimSpace(row[i])) {
+					continue columns
+				}
+			}
+			l.questions = append(l.questions, i)
+		}
+	}
+	if len(l.questions) == 0 {
+		return nil, nil, fmt.Errorf("no question columns found")
+	}
+	questions := make([]Question, 0, len(l.questions))
+	for _, i := range l.questions {
+		text := strings.TrimSpace(row[i])
+		if len(text) == 0 {
+			return nil, nil, fmt.Errorf("column #%d has no title", i+1)
+		}
+		if p.Questions.TrimNumbers {
+			text = trimNumberPrefix(text)
+		}
+		q, err := buildQuestion(text)
+		if err != nil {
+			return nil, nil, err
+		}
+		questions = append(questions, q)
+	}
+	return l, questions, nil
+}
+
+// parseTime parses a timestamp cell.  Numbers are Excel serial dates, as found in the raw
+// cell values of a spreadsheet.
+func (p *Profile) parseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return time.Time{}, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return TimeFromExcelTime(f, false), nil
+	}
+	for _, layout := range append(p.TimeLayouts, time.RFC3339, "2006-01-02T15:04:05", "2006-01-02") {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse time '%s'", s)
+}
+
+// response builds a response from a row of data
+func (l *columnLayout) response(row []string, nquestions int) (Response, error) {
+	var (
+		a   Response
+		err error
+	)
+	cell := func(i int) string {
+		if i >= 0 && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+	if a.Completed, err = l.profile.parseTime(cell(l.timestamp)); err != nil {
+		return a, fmt.Errorf("invalid Completed time (%s) on row:\n%+v", err, row)
+	}
+	a.Email = strings.ToLower(cell(l.email))
+	a.Name = cell(l.name)
+	if len(a.Email) == 0 {
+		a.Email = strings.ToLower(a.Name)
+	}
+	if len(a.Name) == 0 {
+		a.Name = a.Email
+	}
+	for _, i := range l.questions {
+		a.Answers = append(a.Answers, cell(i))
+	}
+	a.AnswerScore = make([]int, nquestions)
+	return a, nil
+}
+
+// ParseRows builds the questions and responses from the rows of an export, for readers
+// which get rows from somewhere other than a file.  Row 1 has the column titles and rows
+// 2+ have the data.  The layout is found with the profile in the options, or else with
+// the first built-in profile which matches the column titles.
+func ParseRows(rows [][]string, opts ReadOptions) ([]Question, []Response, error) {
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("no column titles found, the file is empty")
+	}
+	layout, questions, err := findLayout(rows[0], opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	list := make([]Response, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		if blankRow(row) {
+			continue
+		}
+		a, err := layout.response(row, len(questions))
+		if err != nil {
+			return nil, nil, err
+		}
+		list = append(list, a)
+	}
+	return questions, list, nil
+}
+
+// findLayout checks the column titles to see if the export is of an expected format
+func findLayout(header []string, opts ReadOptions) (*columnLayout, []Question, error) {
+	if opts.Profile != nil {
+		layout, questions, err := opts.Profile.match(header)
+		if err != nil {
+			return nil, nil, fmt.Errorf("column titles do not match profile %s: %s", opts.Profile.Name, err)
+		}
+		return layout, questions, nil
+	}
+	reasons := make([]string, 0, len(builtinProfiles))
+	for _, p := range builtinProfiles {
+		layout, questions, err := p.match(header)
+		if err == nil {
+			return layout, questions, nil
+		}
+		reasons = append(reasons, fmt.Sprintf("%s: %s", p.Name, err))
+	}
+	return nil, nil, fmt.Errorf("column titles do not match any known layout (%s)", strings.Join(reasons, "; "))
+}
+
+func blankRow(row []string) bool {
+	for _, c := range row {
+		if len(strings.TrimSpace(c)) > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+package sheep
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProfile_match(t *testing.T) {
+	msQuiz := []string{"ID", "Start time", "Completion time", "Email", "Name", "Total points", "Quiz feedback",
+		"A sweetener", "Points - A sweetener", "Feedback - A sweetener",
+		"🎯 A color [Blue]", "Points - 🎯 A color [Blue]", "Feedback - 🎯 A color [Blue]"}
+	msModified := []string{"ID", "Start time", "Completion time", "Email", "Name", "Last modified time", "A sweetener", "A color"}
+	google := []string{"Timestamp", "Email Address", "Your Name (first and last)", "1. A sweetener", "2. A color"}
+	tests := []struct {
+		name      string
+		profile   string
+		header    []string
+		wantErr   bool
+		wantCols  []int
+		wantTexts []string
+	}{
+		{"microsoft quiz", "microsoft-forms", msQuiz, false, []int{7, 10}, []string{"A sweetener", "🎯 A color"}},
+		{"microsoft last modified", "microsoft-forms", msModified, false, []int{6, 7}, []string{"A sweetener", "A color"}},
+		{"microsoft on google", "microsoft-forms", google, true, nil, nil},
+		{"google", "google-forms", google, false, []int{3, 4}, []string{"A sweetener", "A color"}},
+		{"google without name", "google-forms", googleHeader, false, []int{2, 3}, []string{"A sweetener", "A color"}},
+		{"google on microsoft", "google-forms", msModified, true, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := LoadProfile(tt.profile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			layout, questions, err := p.match(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("match() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(layout.questions) != len(tt.wantCols) {
+				t.Fatalf("match() questions = %v, want %v", layout.questions, tt.wantCols)
+			}
+			for i := range tt.wantCols {
+				if layout.questions[i] != tt.wantCols[i] || questions[i].Text != tt.wantTexts[i] {
+					t.Errorf("question %d = column %d '%s', want column %d '%s'", i, layout.questions[i], questions[i].Text, tt.wantCols[i], tt.wantTexts[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseProfile(t *testing.T) {
+	p, err := ParseProfile([]byte(`{
+		"name": "survey-tool",
+		"timestamp": {"index": 1},
+		"timeLayouts": ["02.01.2006 15:04"],
+		"email": {"regex": "(?i)e-?mail"},
+		"playerName": {"header": "Who are you?", "optional": true},
+		"questions": {"columns": [{"regex": "^Q1\\b"}, {"index": 5}]}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := [][]string{
+		{"When", "Who are you?", "E-Mail", "Q1 A sweetener", "Q2 A color", "Comments"},
+		{"15.03.2024 10:00", "Al", "AL@acme.com", "Sugar", "Red", "fun"},
+		{},
+	}
+	questions, responses, err := ParseRows(rows, ReadOptions{Profile: p})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(questions) != 2 || questions[1].Text != "Q2 A color" {
+		t.Errorf("questions = %+v", questions)
+	}
+	want := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	if len(responses) != 1 || responses[0].Email != "al@acme.com" || !responses[0].Completed.Equal(want) ||
+		responses[0].Answers[1] != "Red" {
+		t.Errorf("responses = %+v", responses)
+	}
+	if _, err = ParseProfile([]byte(`{"email": {"regex": "("}}`)); err == nil {
+		t.Errorf("ParseProfile() with bad regex did not fail")
+	}
+}
+
+func TestProfile_parseTime(t *testing.T) {
+	google, _ := LoadProfile("google-forms")
+	tests := []struct {
+		s       string
+		want    time.Time
+		wantErr bool
+	}{
+		{"3/15/2024 10:23:45", time.Date(2024, 3, 15, 10, 23, 45, 0, time.UTC), false},
+		{"45366.5", time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC), false},
+		{"2024-03-15T10:23:45Z", time.Date(2024, 3, 15, 10, 23, 45, 0, time.UTC), false},
+		{"", time.Time{}, false},
+		{"yesterday", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := google.parseTime(tt.s)
+			if (err != nil) != tt.wantErr || !got.Equal(tt.want) {
+				t.Errorf("parseTime(%q) = %v, %v, want %v", tt.s, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,341 @@
+// Package sheep tabulates the answers to a Sheep quiz, where an answer scores
+// the number of players who gave the same answer.
+package sheep
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Response struct {
+	Email       string
+	Name        string
+	Team        string
+	Completed   time.Time
+	Answers     []string
+	RawAnswers  []string // Answers as read, before any aliases were applied
+	AnswerScore []int
+	RawScore    []int // AnswerScore before the question's weight
+	TotalScore  int
+	TotalBonus  int    // points from bonus answers, included in TotalScore
+	Source      string // File the response was read from
+}
+
+type Member struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+	Team  string `json:"-"`
+}
+
+// Teams maps a team name to its members
+type Teams map[string][]Member
+
+type PopulationCount struct {
+	Freq           int
+	OriginalAnswer string
+	Bonus          int // If this was a bonus answer, what's the bonus value
+}
+
+type Question struct {
+	Text             string
+	Numeric          bool // answers are numbers, so "7" and "Seven." are the same answer
+	Multi            bool // answers are lists of several answers, each counted on its own
+	BonusQuestion    bool
+	BonusAnswers     []string // answers which earn the bonus
+	Bonus            BonusRule
+	BonusValue       int
+	Weight           float64 // the answers' scores are multiplied by the weight; 0 is the same as 1
+	Scorer           Scorer  // how the answers score; nil uses the quiz's scorer
+	PopulationCounts map[string]*PopulationCount
+}
+
+// Quiz holds everything needed to tabulate one quiz.  Teams is nil unless the
+// quiz is being scored in teams mode.
+type Quiz struct {
+	Questions  []Question
+	Responses  []Response
+	Teams      Teams
+	Canon      *Canonicalizer      // how answers are made into keys; nil uses DefaultCanonicalizer
+	Scorer     Scorer              // how answers score unless a question has its own; nil uses FrequencyScorer
+	Removed    []Removal           // answers removed by moderation
+	PinkCow    *PinkCowGame        // nil unless the quiz is played with the pink cow
+	rules      [][]Rule            // rewrite rules for each question, set with SetRules
+	dictionary *Dictionary         // synonyms from other quizzes, set with SetDictionary
+	canonical  []map[string]string // answer key to alias for each question, made by ApplyAliases
+}
+
+// TeamMode reports whether the quiz is being scored in teams mode
+func (q *Quiz) TeamMode() bool {
+	return q.Teams != nil
+}
+
+// ReadResponses reads the questions and responses from the input file with the reader
+// which claims it, replacing any already in the quiz.  In teams mode every response must
+// come from a team member.
+func (q *Quiz) ReadResponses(filename string, opts ReadOptions) error {
+	reader, err := FindReader(filename)
+	if err != nil {
+		return err
+	}
+	questions, responses, err := reader.Read(filename, opts)
+	if err != nil {
+		return err
+	}
+	for i := range responses {
+		if len(responses[i].Source) == 0 {
+			responses[i].Source = filename
+		}
+	}
+	if q.TeamMode() {
+		for i := range responses {
+			if responses[i].Team, err = q.Teams.FindTeam(responses[i].Email); err != nil {
+				return err
+			}
+		}
+	}
+	q.Questions, q.Responses = questions, responses
+	return nil
+}
+
+// AddResponses reads more responses for the quiz from another file, which may be in a
+// different format.  The file must ask the same questions in the same order.
+func (q *Quiz) AddResponses(filename string, opts ReadOptions) error {
+	if len(q.Questions) == 0 && len(q.Responses) == 0 {
+		return q.ReadResponses(filename, opts)
+	}
+	more := Quiz{Teams: q.Teams}
+	if err := more.ReadResponses(filename, opts); err != nil {
+		return err
+	}
+	if err := sameQuestions(q.Questions, more.Questions); err != nil {
+		return fmt.Errorf("questions in %s do not match: %s", filename, err)
+	}
+	q.Responses = append(q.Responses, more.Responses...)
+	return nil
+}
+
+// ReadFiles reads the questions and responses from one or more files, as with
+// AddResponses, replacing any already in the quiz
+func (q *Quiz) ReadFiles(filenames []string, opts ReadOptions) error {
+	q.Questions, q.Responses = nil, nil
+	for _, filename := range filenames {
+		if err := q.AddResponses(filename, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sameQuestions checks that two lists ask the same questions, ignoring any number prefix
+func sameQuestions(a, b []Question) error {
+	if len(a) != len(b) {
+		return fmt.Errorf("%d questions instead of %d", len(b), len(a))
+	}
+	for i := range a {
+		x, y := strings.TrimSpace(trimNumberPrefix(a[i].Text)), strings.TrimSpace(trimNumberPrefix(b[i].Text))
+		if !strings.EqualFold(x, y) {
+			return fmt.Errorf("question #%d is '%s' instead of '%s'", i+1, y, x)
+		}
+	}
+	return nil
+}
+
+func (q *Question) mostFreqAnswer() int {
+	max := 0
+	for _, v := range q.PopulationCounts {
+		if v.Freq > max {
+			max = v.Freq
+		}
+	}
+	return max
+}
+
+// CalcScores builds the answer frequencies for each question and scores every response
+// with the scorer and weight of each question, then passes the pink cow if the quiz is
+// played with it.  Each entry of an answer to a multi-answer question is counted and scored
+// on its own.  It may be called again after the responses are edited.
+func (q *Quiz) CalcScores() {
+	questions, responses := q.Questions, q.Responses
+	for idxQ := range questions {
+		questions[idxQ].PopulationCounts = make(map[string]*PopulationCount)
+	}
+
+	// First create a map for each question with the frequency of each answer
+	for _, r := range responses {
+		for i, answerText := range r.Answers {
+			for _, entry := range q.AnswerEntries(i, answerText) {
+				a := q.AnswerKey(i, entry)
+				if pc, exists := questions[i].PopulationCounts[a]; exists {
+					pc.Freq++
+				} else {
+					original := entry
+					if _, ok := numericKey(entry); ok && questions[i].Numeric {
+						original = a // numbers are shown the same way however they were written
+					}
+					questions[i].PopulationCounts[a] = &PopulationCount{Freq: 1, OriginalAnswer: original}
+				}
+			}
+		}
+	}
+
+	// Calculate value of bonus answers
+	for idxQ := range questions {
+		if questions[idxQ].BonusQuestion {
+			questions[idxQ].BonusValue = questions[idxQ].Bonus.value(questions[idxQ].mostFreqAnswer())
+			for _, answer := range questions[idxQ].BonusAnswers {
+				if pc, exists := questions[idxQ].PopulationCounts[q.AnswerKey(idxQ, answer)]; exists {
+					pc.Bonus = questions[idxQ].BonusValue
+				}
+			}
+		}
+	}
+
+	// Now go through the answers in each response and assign the score to each with the question's scorer
+	for idxR := range responses {
+		responses[idxR].TotalScore, responses[idxR].TotalBonus = 0, 0
+		responses[idxR].RawScore = make([]int, len(responses[idxR].AnswerScore))
+		for i, a := range responses[idxR].Answers {
+			score, bonus := 0, 0
+			for _, entry := range q.AnswerEntries(i, a) {
+				pc := questions[i].PopulationCounts[q.AnswerKey(i, entry)]
+				s := q.QuestionScorer(i).Score(pc.Freq, &questions[i], len(responses))
+				if pc.Bonus > 0 {
+					if questions[i].Bonus.Additive {
+						s += pc.Bonus
+					} else {
+						s = pc.Bonus
+					}
+					bonus += pc.Bonus
+				}
+				score += s
+			}
+			responses[idxR].TotalBonus += questions[i].weigh(bonus)
+			responses[idxR].RawScore[i] = score
+			responses[idxR].AnswerScore[i] = questions[i].weigh(score)
+			responses[idxR].TotalScore += responses[idxR].AnswerScore[i]
+		}
+	}
+	if q.PinkCow != nil {
+		q.passPinkCow()
+	}
+}
+
+// EliminateDups removes duplicate responses by a member, keeping the last completed one only.
+// The responses which were dropped are returned.
+func (q *Quiz) EliminateDups() []Response {
+	responses := q.Responses
+	sort.Slice(responses, func(i, j int) bool {
+		if responses[i].Email == responses[j].Email {
+			return responses[i].Completed.Before(responses[j].Completed)
+		}
+		return responses[i].Email < responses[j].Email
+	})
+	var (
+		last    Response
+		dropped []Response
+	)
+	newlist := make([]Response, 0, len(responses))
+	for i, a := range responses {
+		if i > 0 {
+			if a.Email != last.Email {
+				newlist = append(newlist, last)
+			} else {
+				dropped = append(dropped, last)
+			}
+		}
+		last = a
+	}
+	if len(last.Email) > 0 {
+		newlist = append(newlist, last)
+	}
+	q.Responses = newlist
+	return dropped
+}
+
+// buildQuestion makes a question from a column title.  Markers at the start of the title
+// tag the question: 🎯 for a bonus question, whose bonus answers and options end the title
+// in brackets, 🔢 for a numeric question, 📋 for a question asking for several answers, and
+// ×2 for a question whose scores are doubled.
+func buildQuestion(text string) (q Question, err error) {
+	markers, weight, err := titleMarkers(text)
+	if err != nil {
+		return
+	}
+	if strings.ContainsRune(markers, '🎯') {
+		// Bonus question.  Expect bonus answers to be on the end of the title: "Question [answer|answer; options]"
+		regx := regexp.MustCompile(`(?U)(^.+)\s*\[(.*)\]\s*$`)
+		if matches := regx.FindStringSubmatch(text); matches == nil {
+			err = fmt.Errorf("bonus question is missing ending answer <%s [answer]>", text)
+			return
+		} else {
+			q = Question{
+				Text:             matches[1],
+				BonusQuestion:    true,
+				PopulationCounts: make(map[string]*PopulationCount),
+			}
+			if q.BonusAnswers, q.Bonus, err = parseBonusSpec(matches[2]); err != nil {
+				err = fmt.Errorf("%s: %s", text, err)
+				return
+			}
+		}
+	} else {
+		q = Question{Text: text, PopulationCounts: make(map[string]*PopulationCount)}
+	}
+	q.Numeric = strings.ContainsRune(markers, numericMarker)
+	q.Multi = strings.ContainsRune(markers, multiMarker)
+	q.Weight = weight
+	return
+}
+
+// ReadTeams reads the JSON teams file
+func ReadTeams(filename string) (Teams, error) {
+	var teams Teams
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, &teams); err != nil {
+		return nil, err
+	}
+	for teamName, members := range teams {
+		for i := range members {
+			members[i].Email = strings.ToLower(members[i].Email)
+			members[i].Team = teamName
+		}
+	}
+	return teams, nil
+}
+
+// Names returns the team names in sorted order
+func (t Teams) Names() []string {
+	names := make([]string, 0, len(t))
+	for n := range t {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (t Teams) TotalMembers() int {
+	m := 0
+	for _, v := range t {
+		m += len(v)
+	}
+	return m
+}
+
+func (t Teams) FindTeam(email string) (string, error) {
+	for _, v := range t {
+		for _, m := range v {
+			if m.Email == email {
+				return m.Team, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("cannot find '%s' on any team", email)
+}
@@ -0,0 +1,116 @@
+package sheep
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestQuiz(titles []string, answers ...[]string) *Quiz {
+	q := &Quiz{}
+	for _, t := range titles {
+		question, _ := buildQuestion(t)
+		q.Questions = append(q.Questions, question)
+	}
+	for i, a := range answers {
+		q.Responses = append(q.Responses, Response{
+			Email:       string(rune('a'+i)) + "@acme.com",
+			Name:        string(rune('A' + i)),
+			Answers:     a,
+			AnswerScore: make([]int, len(titles)),
+		})
+	}
+	return q
+}
+
+func TestQuiz_CalcScores(t *testing.T) {
+	q := newTestQuiz([]string{"A flavor of ice cream", "🎯 A fruit [Kiwi]"},
+		[]string{"Chocolate", "Apple"},
+		[]string{"chocolate", "Apple"},
+		[]string{"Vanilla", "kiwi"},
+		[]string{"", "Apple"},
+	)
+	q.CalcScores()
+	want := []int{5, 5, 5, 3}
+	for i, r := range q.Responses {
+		if r.TotalScore != want[i] {
+			t.Errorf("response %d TotalScore = %d, want %d", i, r.TotalScore, want[i])
+		}
+	}
+	if got := q.Questions[1].BonusValue; got != 4 {
+		t.Errorf("BonusValue = %d, want 4", got)
+	}
+	// Scoring again must not double count
+	q.CalcScores()
+	if got := q.Questions[0].PopulationCounts["chocolate"].Freq; got != 2 {
+		t.Errorf("Freq after rescore = %d, want 2", got)
+	}
+}
+
+func TestQuiz_Independent(t *testing.T) {
+	q1 := newTestQuiz([]string{"A color"}, []string{"Red"}, []string{"Red"})
+	q2 := newTestQuiz([]string{"A number", "A letter"}, []string{"1", "A"})
+	q1.CalcScores()
+	q2.CalcScores()
+	if q1.Responses[0].TotalScore != 2 || q2.Responses[0].TotalScore != 2 {
+		t.Errorf("scores = %d, %d, want 2, 2", q1.Responses[0].TotalScore, q2.Responses[0].TotalScore)
+	}
+}
+
+func TestQuiz_EliminateDups(t *testing.T) {
+	now := time.Now()
+	q := &Quiz{Responses: []Response{
+		{Email: "b@acme.com", Completed: now},
+		{Email: "a@acme.com", Completed: now.Add(time.Minute), Name: "later"},
+		{Email: "a@acme.com", Completed: now, Name: "earlier"},
+	}}
+	dropped := q.EliminateDups()
+	if len(q.Responses) != 2 || len(dropped) != 1 {
+		t.Fatalf("kept %d dropped %d, want 2 and 1", len(q.Responses), len(dropped))
+	}
+	if q.Responses[0].Name != "later" || dropped[0].Name != "earlier" {
+		t.Errorf("kept %q dropped %q, want later and earlier", q.Responses[0].Name, dropped[0].Name)
+	}
+}
+
+func TestQuiz_TeamScores(t *testing.T) {
+	q := newTestQuiz([]string{"A color"}, []string{"Red"}, []string{"Red"}, []string{"Blue"})
+	q.Teams = Teams{
+		"TeamA": {{Email: "a@acme.com", Team: "TeamA"}, {Email: "b@acme.com", Team: "TeamA"}},
+		"TeamB": {{Email: "c@acme.com", Team: "TeamB"}, {Email: "x@acme.com", Team: "TeamB"}},
+	}
+	q.Responses[0].Team, q.Responses[1].Team, q.Responses[2].Team = "TeamA", "TeamA", "TeamB"
+	q.CalcScores()
+	ts := q.TeamScores(MissingAvg)
+	if ts[0].Name != "TeamA" || ts[0].Score != 8 || ts[1].Score != 4 {
+		t.Errorf("TeamScores() = %+v", ts)
+	}
+	if m := q.MissingMembers(); len(m) != 1 || m[0].Email != "x@acme.com" {
+		t.Errorf("MissingMembers() = %+v", m)
+	}
+}
+
+func TestQuiz_ReadFiles(t *testing.T) {
+	google := writeTestFile(t, "google.csv", "Timestamp,Email Address,1. A sweetener,2. A color\n"+
+		"3/15/2024 10:00:00,a@acme.com,Sugar,Red\n3/15/2024 10:00:00,b@acme.com,Honey,Red\n")
+	microsoft := writeTestXLSX(t, "ms.xlsx", [][]string{
+		{"ID", "Start time", "Completion time", "Email", "Name", "A Sweetener", "A color"},
+		{"1", "45366.41", "45366.9", "A@acme.com", "Al", "Stevia", "Blue"},
+	})
+	other := writeTestFile(t, "other.csv", "Timestamp,Email Address,1. A sweetener,2. A number\n")
+
+	var q Quiz
+	if err := q.ReadFiles([]string{google, microsoft}, ReadOptions{}); err != nil {
+		t.Fatalf("ReadFiles() error = %v", err)
+	}
+	if len(q.Responses) != 3 || q.Responses[2].Source != microsoft {
+		t.Fatalf("responses = %+v", q.Responses)
+	}
+	dropped := q.EliminateDups()
+	if len(dropped) != 1 || dropped[0].Source != google || q.Responses[0].Answers[0] != "Stevia" {
+		t.Errorf("EliminateDups() dropped %+v kept %+v", dropped, q.Responses[0])
+	}
+	if err := q.AddResponses(other, ReadOptions{}); err == nil || !strings.Contains(err.Error(), "'A number' instead of 'A color'") {
+		t.Errorf("AddResponses() error = %v", err)
+	}
+}
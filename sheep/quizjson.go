@@ -0,0 +1,265 @@
+package sheep
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The canonical quiz format is a JSON document holding the questions and responses of a
+// quiz along with the computed scores.  A JSONL file holds the same document without its
+// responses on the first line, followed by one response per line.  The scores are written
+// for other tools to use and are recomputed when a quiz is read.  quiz.schema.json is the
+// JSON Schema of the format.
+const (
+	QuizFormat        = "sheep-quiz"
+	QuizFormatVersion = 1
+)
+
+type quizJSON struct {
+	Format    string         `json:"format"`
+	Version   int            `json:"version"`
+	Questions []questionJSON `json:"questions"`
+	Responses []responseJSON `json:"responses,omitempty"`
+}
+
+type questionJSON struct {
+	Text            string       `json:"text"`
+	Numeric         bool         `json:"numeric,omitempty"`
+	Multi           bool         `json:"multi,omitempty"`
+	Scoring         string       `json:"scoring,omitempty"`
+	Weight          float64      `json:"weight,omitempty"`
+	BonusAnswer     *string      `json:"bonusAnswer,omitempty"`  // only bonus questions have a bonus answer
+	BonusAnswers    []string     `json:"bonusAnswers,omitempty"` // instead of bonusAnswer when there are several
+	BonusMultiplier float64      `json:"bonusMultiplier,omitempty"`
+	BonusPoints     int          `json:"bonusPoints,omitempty"`
+	BonusAdditive   bool         `json:"bonusAdditive,omitempty"`
+	BonusValue      int          `json:"bonusValue,omitempty"`
+	Answers         []answerJSON `json:"answers,omitempty"`
+}
+
+// answerJSON is one distinct answer to a question and how many players gave it
+type answerJSON struct {
+	Answer string `json:"answer"`
+	Freq   int    `json:"freq"`
+}
+
+type responseJSON struct {
+	Email      string    `json:"email"`
+	Name       string    `json:"name,omitempty"`
+	Team       string    `json:"team,omitempty"`
+	Completed  time.Time `json:"completed"`
+	Answers    []string  `json:"answers"`
+	RawAnswers []string  `json:"rawAnswers,omitempty"`
+	Scores     []int     `json:"scores,omitempty"`
+	TotalScore int       `json:"totalScore"`
+	TotalBonus int       `json:"totalBonus,omitempty"`
+	Source     string    `json:"source,omitempty"`
+}
+
+func (q *Quiz) toJSON(withResponses bool) quizJSON {
+	doc := quizJSON{Format: QuizFormat, Version: QuizFormatVersion, Questions: make([]questionJSON, 0, len(q.Questions))}
+	for i := range q.Questions {
+		question := &q.Questions[i]
+		qj := questionJSON{Text: question.Text, Numeric: question.Numeric, Multi: question.Multi, Weight: question.Weight}
+		if question.Scorer != nil || q.Scorer != nil {
+			qj.Scoring = q.QuestionScorer(i).Name()
+		}
+		if question.BonusQuestion {
+			if len(question.BonusAnswers) == 1 {
+				answer := question.BonusAnswers[0]
+				qj.BonusAnswer = &answer
+			} else {
+				qj.BonusAnswers = question.BonusAnswers
+			}
+			qj.BonusMultiplier = question.Bonus.Multiplier
+			qj.BonusPoints = question.Bonus.Points
+			qj.BonusAdditive = question.Bonus.Additive
+			qj.BonusValue = question.BonusValue
+		}
+		for _, p := range question.SortedCounts(false) {
+			qj.Answers = append(qj.Answers, answerJSON{Answer: p.OriginalAnswer, Freq: p.Freq})
+		}
+		doc.Questions = append(doc.Questions, qj)
+	}
+	if withResponses {
+		doc.Responses = make([]responseJSON, 0, len(q.Responses))
+		for i := range q.Responses {
+			doc.Responses = append(doc.Responses, responseToJSON(&q.Responses[i]))
+		}
+	}
+	return doc
+}
+
+func responseToJSON(r *Response) responseJSON {
+	return responseJSON{
+		Email:      r.Email,
+		Name:       r.Name,
+		Team:       r.Team,
+		Completed:  r.Completed,
+		Answers:    r.Answers,
+		RawAnswers: r.RawAnswers,
+		Scores:     r.AnswerScore,
+		TotalScore: r.TotalScore,
+		TotalBonus: r.TotalBonus,
+		Source:     r.Source,
+	}
+}
+
+// WriteJSON writes the quiz as one JSON document
+func (q *Quiz) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(q.toJSON(true))
+}
+
+// WriteJSONL writes the quiz without its responses on the first line, followed by one
+// response per line
+func (q *Quiz) WriteJSONL(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(q.toJSON(false)); err != nil {
+		return err
+	}
+	for i := range q.Responses {
+		if err := enc.Encode(responseToJSON(&q.Responses[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Save writes the quiz in the canonical format, as JSONL if the file name ends in .jsonl
+// and as JSON otherwise
+func (q *Quiz) Save(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if strings.EqualFold(filepath.Ext(filename), ".jsonl") {
+		err = q.WriteJSONL(w)
+	} else {
+		err = q.WriteJSON(w)
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// isQuizJSON reports whether the start of a file looks like the canonical quiz format
+func isQuizJSON(head []byte) bool {
+	head = bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\ufeff")), " \t\r\n")
+	return bytes.HasPrefix(head, []byte("{")) && bytes.Contains(head, []byte(`"`+QuizFormat+`"`))
+}
+
+// quizJSONReader reads the canonical JSON or JSONL quiz format
+type quizJSONReader struct{}
+
+func (quizJSONReader) Name() string { return "Quiz JSON" }
+
+func (quizJSONReader) Claim(filename string, head []byte) bool {
+	return isQuizJSON(head)
+}
+
+func (quizJSONReader) Read(filename string, opts ReadOptions) ([]Question, []Response, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	return readQuizJSON(f)
+}
+
+// readQuizJSON reads a quiz in JSON or JSONL form.  Either way the first value is the quiz
+// document, and any values after it are more responses.
+func readQuizJSON(r io.Reader) ([]Question, []Response, error) {
+	var doc quizJSON
+	dec := json.NewDecoder(r)
+	if err := dec.Decode(&doc); err != nil {
+		return nil, nil, err
+	}
+	if doc.Format != QuizFormat {
+		return nil, nil, fmt.Errorf("format is '%s', not '%s'", doc.Format, QuizFormat)
+	}
+	if doc.Version != QuizFormatVersion {
+		return nil, nil, fmt.Errorf("unsupported %s version %d", QuizFormat, doc.Version)
+	}
+	for line := 2; dec.More(); line++ {
+		var rj responseJSON
+		if err := dec.Decode(&rj); err != nil {
+			return nil, nil, fmt.Errorf("line %d: %s", line, err)
+		}
+		doc.Responses = append(doc.Responses, rj)
+	}
+	questions := make([]Question, 0, len(doc.Questions))
+	for i, qj := range doc.Questions {
+		if len(qj.Text) == 0 {
+			return nil, nil, fmt.Errorf("question #%d has no text", i+1)
+		}
+		if qj.Weight < 0 {
+			return nil, nil, fmt.Errorf("question #%d has a negative weight", i+1)
+		}
+		question := Question{Text: qj.Text, Numeric: qj.Numeric, Multi: qj.Multi, Weight: qj.Weight, PopulationCounts: make(map[string]*PopulationCount)}
+		if qj.BonusAnswer != nil {
+			question.BonusAnswers = append(question.BonusAnswers, *qj.BonusAnswer)
+		}
+		question.BonusAnswers = append(question.BonusAnswers, qj.BonusAnswers...)
+		if len(question.BonusAnswers) > 0 {
+			question.BonusQuestion = true
+			question.Bonus = BonusRule{Multiplier: qj.BonusMultiplier, Points: qj.BonusPoints, Additive: qj.BonusAdditive}
+		}
+		if len(qj.Scoring) > 0 {
+			scorer, err := ParseScorer(qj.Scoring)
+			if err != nil {
+				return nil, nil, fmt.Errorf("question #%d: %s", i+1, err)
+			}
+			question.Scorer = scorer
+		}
+		questions = append(questions, question)
+	}
+	list := make([]Response, 0, len(doc.Responses))
+	for i, rj := range doc.Responses {
+		if len(rj.Email) == 0 {
+			return nil, nil, fmt.Errorf("response #%d has no email", i+1)
+		}
+		if len(rj.Answers) > len(questions) {
+			return nil, nil, fmt.Errorf("response #%d from %s has %d answers for %d questions", i+1, rj.Email, len(rj.Answers), len(questions))
+		}
+		a := Response{
+			Email:       strings.ToLower(rj.Email),
+			Name:        rj.Name,
+			Team:        rj.Team,
+			Completed:   rj.Completed,
+			Source:      rj.Source,
+			AnswerScore: make([]int, len(questions)),
+		}
+		if len(a.Name) == 0 {
+			a.Name = a.Email
+		}
+		for _, answer := range rj.Answers {
+			a.Answers = append(a.Answers, strings.TrimSpace(answer))
+		}
+		if rj.RawAnswers != nil {
+			if len(rj.RawAnswers) != len(rj.Answers) {
+				return nil, nil, fmt.Errorf("response #%d from %s has %d raw answers for %d answers", i+1, rj.Email, len(rj.RawAnswers), len(rj.Answers))
+			}
+			for _, answer := range rj.RawAnswers {
+				a.RawAnswers = append(a.RawAnswers, strings.TrimSpace(answer))
+			}
+		}
+		list = append(list, a)
+	}
+	return questions, list, nil
+}
@@ -0,0 +1,88 @@
+package sheep
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuiz_Save(t *testing.T) {
+	q := newTestQuiz([]string{"A flavor of ice cream", "🎯 A fruit [Kiwi]"},
+		[]string{"Chocolate", "Apple"},
+		[]string{"chocolate", "Kiwi"},
+		[]string{"Vanilla"},
+	)
+	q.Responses[0].Completed = time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	q.Responses[1].Team = "TeamA"
+	q.Responses[1].RawAnswers = []string{"choc", "Kiwi"}
+	q.CalcScores()
+	for _, name := range []string{"quiz.json", "quiz.jsonl"} {
+		t.Run(name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), name)
+			if err := q.Save(filename); err != nil {
+				t.Fatal(err)
+			}
+			var got Quiz
+			if err := got.ReadResponses(filename, ReadOptions{}); err != nil {
+				t.Fatalf("ReadResponses() error = %v", err)
+			}
+			got.CalcScores()
+			if len(got.Questions) != 2 || strings.Join(got.Questions[1].BonusAnswers, "|") != "Kiwi" || got.Questions[1].BonusValue != q.Questions[1].BonusValue {
+				t.Errorf("questions = %+v", got.Questions)
+			}
+			for i := range q.Responses {
+				want, r := q.Responses[i], got.Responses[i]
+				if r.Email != want.Email || r.Team != want.Team || !r.Completed.Equal(want.Completed) ||
+					!reflect.DeepEqual(r.Answers, want.Answers) || !reflect.DeepEqual(r.RawAnswers, want.RawAnswers) || r.TotalScore != want.TotalScore {
+					t.Errorf("response %d = %+v, want %+v", i, r, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReadQuizJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"jsonl", `{"format":"sheep-quiz","version":1,"questions":[{"text":"A color"}]}
+{"email":"A@acme.com","completed":"2024-03-15T10:00:00Z","answers":["Red"]}
+{"email":"b@acme.com","completed":"2024-03-15T10:00:00Z","answers":["Blue"]}
+`, ""},
+		{"wrong format", `{"format":"other","version":1,"questions":[]}`, "not 'sheep-quiz'"},
+		{"wrong version", `{"format":"sheep-quiz","version":2,"questions":[]}`, "unsupported"},
+		{"too many answers", `{"format":"sheep-quiz","version":1,"questions":[{"text":"A color"}],
+			"responses":[{"email":"a@acme.com","completed":"2024-03-15T10:00:00Z","answers":["Red","Blue"]}]}`, "2 answers for 1 questions"},
+		{"bad line", `{"format":"sheep-quiz","version":1,"questions":[{"text":"A color"}]}
+{"email":`, "line 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, responses, err := readQuizJSON(strings.NewReader(tt.input))
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("readQuizJSON() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(responses) != 2 || responses[0].Email != "a@acme.com" || responses[0].Name != "a@acme.com" {
+				t.Errorf("responses = %+v", responses)
+			}
+		})
+	}
+	if !isQuizJSON([]byte("\n  {\"format\": \"sheep-quiz\"")) || isQuizJSON([]byte(`{"email": "a@acme.com"}`)) {
+		t.Errorf("isQuizJSON() did not recognize the format")
+	}
+	var b bytes.Buffer
+	if err := (&Quiz{}).WriteJSONL(&b); err != nil || strings.Count(b.String(), "\n") != 1 {
+		t.Errorf("WriteJSONL() of empty quiz = %q, %v", b.String(), err)
+	}
+}
@@ -0,0 +1,163 @@
+package sheep
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ResponseReader reads the questions and responses from one kind of input.  Readers are
+// registered with RegisterReader and are asked in turn whether they can read a file.
+type ResponseReader interface {
+	// Name identifies the reader in messages
+	Name() string
+	// Claim reports whether the reader recognizes the input.  head holds the first bytes
+	// of the file, or is nil if the input is not a local file.  Claim may open the file
+	// to look at its header row.
+	Claim(filename string, head []byte) bool
+	Read(filename string, opts ReadOptions) ([]Question, []Response, error)
+}
+
+// ReadOptions control how responses are read
+type ReadOptions struct {
+	// Profile gives the column layout.  When nil, the layout is found by trying the
+	// built-in profiles against the column titles.
+	Profile *Profile
+	// Sheet names the sheet of a workbook to read.  When empty, the first sheet with the
+	// expected column titles is read; AllSheets reads every such sheet as a round.
+	Sheet string
+}
+
+// sniffLen is how much of the start of a file is handed to ResponseReader.Claim
+const sniffLen = 512
+
+var (
+	readersMu sync.RWMutex
+	readers   []ResponseReader
+)
+
+// RegisterReader adds a reader to the registry.  Readers registered later are asked
+// before those registered earlier, so a new reader can take over a format.
+func RegisterReader(r ResponseReader) {
+	readersMu.Lock()
+	defer readersMu.Unlock()
+	readers = append(readers, r)
+}
+
+// Readers returns the registered readers in the order they are asked to claim a file
+func Readers() []ResponseReader {
+	readersMu.RLock()
+	defer readersMu.RUnlock()
+	list := make([]ResponseReader, 0, len(readers))
+	for i := len(readers) - 1; i >= 0; i-- {
+		list = append(list, readers[i])
+	}
+	return list
+}
+
+// FindReader returns the reader which claims the input.  When no reader claims it, the
+// reader for the file extension is returned so its error explains what is wrong.
+func FindReader(filename string) (ResponseReader, error) {
+	head, err := readHead(filename)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range Readers() {
+		if r.Claim(filename, head) {
+			return r, nil
+		}
+	}
+	switch strings.ToUpper(filepath.Ext(filename)) {
+	case ".XLS", ".XLSX":
+		return xlsxReader{}, nil
+	case ".ODS":
+		return odsReader{}, nil
+	case ".CSV":
+		return csvReader{}, nil
+	case ".JSON", ".JSONL":
+		return quizJSONReader{}, nil
+	}
+	return nil, fmt.Errorf("%s is not in a recognized format, must be a spreadsheet (.xlsx or .ods), CSV, or quiz JSON", filename)
+}
+
+// readHead returns the first bytes of a local file, or nil if the input is not a local file
+func readHead(filename string) ([]byte, error) {
+	if strings.Contains(filename, "://") {
+		return nil, nil
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	return head[:n], nil
+}
+
+var zipMagic = []byte("PK\x03\x04")
+
+func isZip(head []byte) bool {
+	return bytes.HasPrefix(head, zipMagic)
+}
+
+// isText reports whether the start of a file looks like UTF-8 text.  The last character
+// may have been cut short when the start was read.
+func isText(head []byte) bool {
+	if bytes.IndexByte(head, 0) >= 0 {
+		return false
+	}
+	for cut := 0; cut < utf8.UTFMax && cut <= len(head); cut++ {
+		if utf8.Valid(head[:len(head)-cut]) {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	RegisterReader(csvReader{})
+	RegisterReader(xlsxReader{})
+	RegisterReader(odsReader{})
+	RegisterReader(quizJSONReader{})
+}
+
+// xlsxReader reads an XLSX workbook, such as those exported from Microsoft Forms or
+// downloaded from Google Sheets
+type xlsxReader struct{}
+
+func (xlsxReader) Name() string { return "XLSX" }
+
+func (xlsxReader) Claim(filename string, head []byte) bool {
+	return isZip(head) && !isODS(head)
+}
+
+func (xlsxReader) Read(filename string, opts ReadOptions) ([]Question, []Response, error) {
+	return readXLSX(filename, opts)
+}
+
+// csvReader reads a CSV exported from Google Forms or Google Sheets, whatever the file is
+// named.  It claims text files whose first line has several comma separated titles.
+type csvReader struct{}
+
+func (csvReader) Name() string { return "CSV" }
+
+func (csvReader) Claim(filename string, head []byte) bool {
+	if head == nil || !isText(head) {
+		return false
+	}
+	header, err := csvHeader(filename)
+	return err == nil && len(header) >= 3
+}
+
+func (csvReader) Read(filename string, opts ReadOptions) ([]Question, []Response, error) {
+	return readCSV(filename, opts)
+}
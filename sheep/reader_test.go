@@ -0,0 +1,156 @@
+package sheep
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var (
+	googleHeader    = []string{"Timestamp", "Email Address", "1. A sweetener", "2. A color"}
+	microsoftHeader = []string{"ID", "Start time", "Completion time", "Email", "Name", "A sweetener", "A color"}
+)
+
+// writeTestXLSX writes the rows to Sheet1 of a new workbook
+func writeTestXLSX(t *testing.T, name string, rows [][]string) string {
+	t.Helper()
+	f := excelize.NewFile()
+	defer f.Close()
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		r := make([]interface{}, len(row))
+		for j := range row {
+			r[j] = row[j]
+		}
+		if err := f.SetSheetRow("Sheet1", cell, &r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	filename := filepath.Join(t.TempDir(), name)
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestFindReader(t *testing.T) {
+	csvText := "Timestamp,Email Address,1. A sweetener,2. A color\n03/15/2024 10:00:00,a@acme.com,Sugar,Red\n"
+	tests := []struct {
+		name     string
+		filename string
+		want     string
+	}{
+		{"Google CSV", writeTestFile(t, "responses.csv", csvText), "CSV"},
+		{"Google CSV saved as txt", writeTestFile(t, "responses.txt", "\ufeff"+csvText), "CSV"},
+		{"Microsoft Forms", writeTestXLSX(t, "ms.xlsx", [][]string{microsoftHeader,
+			{"1", "45366.41", "45366.42", "A@acme.com", "Al", "Sugar", "Red"}}), "XLSX"},
+		{"Google Sheets", writeTestXLSX(t, "gs.xlsx", [][]string{googleHeader,
+			{"45366.42", "a@acme.com", "Sugar", "Red"}}), "XLSX"},
+		{"bad CSV falls back on extension", writeTestFile(t, "bad.csv", "Time,Email\n"), "CSV"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := FindReader(tt.filename)
+			if err != nil {
+				t.Fatalf("FindReader() error = %v", err)
+			}
+			if r.Name() != tt.want {
+				t.Errorf("FindReader() = %s, want %s", r.Name(), tt.want)
+			}
+		})
+	}
+	if _, err := FindReader(writeTestFile(t, "notes.txt", "hello\n")); err == nil {
+		t.Errorf("FindReader() on unrecognized file did not fail")
+	}
+}
+
+func TestQuiz_ReadResponses(t *testing.T) {
+	filename := writeTestXLSX(t, "gs.xlsx", [][]string{googleHeader,
+		{"45366.42", "a@acme.com", "Sugar", "Red"},
+		{"45366.43", "b@acme.com", "Stevia", "Blue"},
+	})
+	var q Quiz
+	if err := q.ReadResponses(filename, ReadOptions{}); err != nil {
+		t.Fatalf("ReadResponses() error = %v", err)
+	}
+	if len(q.Questions) != 2 || q.Questions[0].Text != "A sweetener" {
+		t.Errorf("Questions = %+v", q.Questions)
+	}
+	if len(q.Responses) != 2 || q.Responses[1].Answers[0] != "Stevia" || q.Responses[0].Completed.Year() != 2024 {
+		t.Errorf("Responses = %+v", q.Responses)
+	}
+}
+
+func TestReadXLSX_sheets(t *testing.T) {
+	f := excelize.NewFile()
+	rounds := map[string][][]interface{}{
+		"Notes": {{"Nothing to see here"}},
+		"Form1": {
+			{"ID", "Start time", "Completion time", "Email", "Name", "A sweetener"},
+			{1, 45366.41, 45366.42, "a@acme.com", "Al", "Sugar"},
+			{2, 45366.41, 45366.43, "b@acme.com", "Bo", "Stevia"},
+		},
+		"Round 2": {
+			{"ID", "Start time", "Completion time", "Email", "Name", "A color"},
+			{1, 45366.51, 45366.52, "b@acme.com", "Bo", "Red"},
+			{2, 45366.51, 45366.53, "c@acme.com", "Cy", "Blue"},
+		},
+	}
+	f.SetSheetName("Sheet1", "Notes")
+	f.NewSheet("Form1")
+	f.NewSheet("Round 2")
+	for sheet, rows := range rounds {
+		for i := range rows {
+			cell, _ := excelize.CoordinatesToCellName(1, i+1)
+			f.SetSheetRow(sheet, cell, &rows[i])
+		}
+	}
+	filename := filepath.Join(t.TempDir(), "rounds.xlsx")
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	tests := []struct {
+		name      string
+		sheet     string
+		wantErr   bool
+		questions int
+		responses int
+	}{
+		{"detect", "", false, 1, 2},
+		{"named", "Round 2", false, 1, 2},
+		{"missing", "Sheet1", true, 0, 0},
+		{"not a quiz", "Notes", true, 0, 0},
+		{"all rounds", AllSheets, false, 2, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			questions, responses, err := readXLSX(filename, ReadOptions{Sheet: tt.sheet})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readXLSX() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(questions) != tt.questions || len(responses) != tt.responses {
+				t.Errorf("readXLSX() = %d questions %d responses, want %d and %d", len(questions), len(responses), tt.questions, tt.responses)
+			}
+			if tt.sheet == AllSheets {
+				for _, r := range responses {
+					if len(r.Answers) != 2 {
+						t.Errorf("%s has answers %q", r.Email, r.Answers)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package sheep
+
+// round is one part of a quiz which was answered separately, such as one sheet of a workbook
+type round struct {
+	questions []Question
+	responses []Response
+}
+
+// joinRounds makes one quiz from several rounds.  The questions of each round follow those
+// of the round before, and the responses of each player are joined by email, leaving the
+// answers blank for any round the player did not answer.  Only the last response by a
+// player in each round is kept.
+func joinRounds(rounds []round) ([]Question, []Response) {
+	var (
+		questions []Question
+		list      []Response
+		byEmail   = make(map[string]int)
+	)
+	for _, rd := range rounds {
+		first := len(questions)
+		questions = append(questions, rd.questions...)
+		q := Quiz{Responses: rd.responses}
+		q.EliminateDups()
+		for _, r := range q.Responses {
+			idx, found := byEmail[r.Email]
+			if !found {
+				idx = len(list)
+				byEmail[r.Email] = idx
+				list = append(list, Response{Email: r.Email, Name: r.Name, Completed: r.Completed})
+			}
+			joined := &list[idx]
+			for len(joined.Answers) < first {
+				joined.Answers = append(joined.Answers, "")
+			}
+			joined.Answers = append(joined.Answers, r.Answers...)
+			for len(joined.Answers) < first+len(rd.questions) {
+				joined.Answers = append(joined.Answers, "")
+			}
+			if r.Completed.After(joined.Completed) {
+				joined.Completed = r.Completed
+			}
+		}
+	}
+	for i := range list {
+		for len(list[i].Answers) < len(questions) {
+			list[i].Answers = append(list[i].Answers, "")
+		}
+		list[i].AnswerScore = make([]int, len(questions))
+	}
+	return questions, list
+}
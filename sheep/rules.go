@@ -0,0 +1,163 @@
+package sheep
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Rule rewrites the answers to a question which match a regular expression.  Replace may
+// refer to submatches as $1 or ${name}, and (?i) at the start of Find ignores case.
+type Rule struct {
+	Find    string `json:"find"`
+	Replace string `json:"replace"`
+	re      *regexp.Regexp
+}
+
+// Rules lists the rules for each question, keyed by number, starting at 1, or by text as
+// in an alias file.  A question's rules run in order, each on what the one before made.
+//
+//	{"questions": {"3": [{"find": "(?i)^(mount|mt\\.?)\\s+", "replace": ""}]}}
+type Rules struct {
+	Questions map[string][]Rule `json:"questions"`
+}
+
+// RuleChange is an answer which a rule rewrote and how many responses gave it
+type RuleChange struct {
+	Before string
+	After  string
+	Count  int
+}
+
+// RuleEffect is what one rule did to the answers of a question
+type RuleEffect struct {
+	Question int // index of the question
+	Rule     Rule
+	Changes  []RuleChange
+}
+
+// RulesFileName is the rules file kept next to a response file: responses.xlsx has
+// responses.rules.json.
+func RulesFileName(filename string) string {
+	return sidecarFile(filename, ".rules.json")
+}
+
+// ReadRules reads a rules file and compiles its rules.  A file which does not exist has
+// no rules.
+func ReadRules(filename string) (*Rules, error) {
+	r := &Rules{}
+	if err := readJSONFile(filename, r); err != nil {
+		return nil, err
+	}
+	for key, rules := range r.Questions {
+		for i := range rules {
+			var err error
+			if rules[i].re, err = regexp.Compile(rules[i].Find); err != nil {
+				return nil, fmt.Errorf("%s: question %s rule %d: %s", filename, key, i+1, err)
+			}
+		}
+	}
+	return r, nil
+}
+
+// Count returns the number of rules for all questions
+func (r *Rules) Count() int {
+	n := 0
+	for _, rules := range r.Questions {
+		n += len(rules)
+	}
+	return n
+}
+
+// SetRules gives the quiz the rules which ApplyAliases runs on the answers as read, before
+// the aliases.  Nil removes them.
+func (q *Quiz) SetRules(r *Rules) error {
+	q.rules = nil
+	if r == nil || len(r.Questions) == 0 {
+		return nil
+	}
+	rules := make([][]Rule, len(q.Questions))
+	// Sort the keys so rules given for the same question by number and by text run in a
+	// predictable order
+	keys := make([]string, 0, len(r.Questions))
+	for key := range r.Questions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		idxQ, err := q.FindQuestion(key)
+		if err != nil {
+			return fmt.Errorf("rules: %s", err)
+		}
+		for _, rule := range r.Questions[key] {
+			if rule.re == nil {
+				if rule.re, err = regexp.Compile(rule.Find); err != nil {
+					return fmt.Errorf("rules: question %s: %s", key, err)
+				}
+			}
+			rules[idxQ] = append(rules[idxQ], rule)
+		}
+	}
+	q.rules = rules
+	return nil
+}
+
+// HasRules reports whether the quiz has rules to run
+func (q *Quiz) HasRules() bool {
+	return q.rules != nil
+}
+
+// rewrite runs the rules for a question on an answer
+func (q *Quiz) rewrite(idxQ int, answer string) string {
+	if idxQ >= len(q.rules) || len(answer) == 0 {
+		return answer
+	}
+	for _, rule := range q.rules[idxQ] {
+		answer = strings.TrimSpace(rule.re.ReplaceAllString(answer, rule.Replace))
+	}
+	return answer
+}
+
+// RuleEffects runs the rules on the answers as read without changing them, and returns
+// what each rule would do.  The rules run on each entry of an answer to a multi-answer
+// question, as in ApplyAliases.
+func (q *Quiz) RuleEffects() []RuleEffect {
+	var effects []RuleEffect
+	for idxQ, rules := range q.rules {
+		answers := make(map[string]int)
+		for _, r := range q.Responses {
+			raw := r.RawAnswers
+			if raw == nil {
+				raw = r.Answers
+			}
+			if idxQ >= len(raw) || len(raw[idxQ]) == 0 {
+				continue
+			}
+			if q.Questions[idxQ].Multi {
+				for _, e := range splitEntries(raw[idxQ]) {
+					answers[e]++
+				}
+			} else {
+				answers[raw[idxQ]]++
+			}
+		}
+		for _, rule := range rules {
+			effect := RuleEffect{Question: idxQ, Rule: rule}
+			next := make(map[string]int, len(answers))
+			for a, n := range answers {
+				after := strings.TrimSpace(rule.re.ReplaceAllString(a, rule.Replace))
+				if after != a {
+					effect.Changes = append(effect.Changes, RuleChange{Before: a, After: after, Count: n})
+				}
+				if len(after) > 0 {
+					next[after] += n
+				}
+			}
+			sort.Slice(effect.Changes, func(i, j int) bool { return effect.Changes[i].Before < effect.Changes[j].Before })
+			effects = append(effects, effect)
+			answers = next
+		}
+	}
+	return effects
+}
@@ -0,0 +1,92 @@
+package sheep
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestQuiz_SetRules(t *testing.T) {
+	filename := writeTestFile(t, "quiz.rules.json", `{"questions": {
+		"1": [{"find": "(?i)^(mount|mt\\.?)\\s+", "replace": ""}],
+		"A volcano": [{"find": "\\s*\\(.*\\)", "replace": ""}, {"find": "(?i)^st\\.?\\s+", "replace": "Saint "}]
+	}}`)
+	rules, err := ReadRules(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules.Count() != 3 {
+		t.Errorf("Count() = %d, want 3", rules.Count())
+	}
+	q := newTestQuiz([]string{"A mountain", "A volcano"},
+		[]string{"Mount Everest", "St. Helens (I climbed it)"},
+		[]string{"Mt Everest", "Saint Helens"},
+		[]string{"Everest", "Etna"},
+		[]string{"mt. Rainier", ""},
+	)
+	if err = q.SetRules(rules); err != nil {
+		t.Fatal(err)
+	}
+	a := &Aliases{}
+	a.Add("2", "Saint Helens", "Mount St. Helens")
+	n, err := q.ApplyAliases(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range q.Responses {
+		got = append(got, r.Answers...)
+	}
+	want := []string{"Everest", "Mount St. Helens", "Everest", "Mount St. Helens", "Everest", "Etna", "Rainier", ""}
+	if !reflect.DeepEqual(got, want) || n != 5 {
+		t.Errorf("ApplyAliases() = %d, answers %q, want 5, %q", n, got, want)
+	}
+	if q.Responses[0].RawAnswers[0] != "Mount Everest" {
+		t.Errorf("RawAnswers = %q", q.Responses[0].RawAnswers)
+	}
+	if err = q.SetRules(&Rules{Questions: map[string][]Rule{"A river": {{Find: "x"}}}}); err == nil {
+		t.Errorf("SetRules() with an unknown question did not fail")
+	}
+	if err = q.SetRules(nil); err != nil || q.HasRules() {
+		t.Errorf("SetRules(nil) = %v, HasRules() = %v", err, q.HasRules())
+	}
+}
+
+func TestReadRules_badPattern(t *testing.T) {
+	filename := writeTestFile(t, "quiz.rules.json", `{"questions": {"2": [{"find": "(", "replace": ""}]}}`)
+	if _, err := ReadRules(filename); err == nil || !strings.Contains(err.Error(), "question 2 rule 1") {
+		t.Errorf("ReadRules() error = %v", err)
+	}
+}
+
+func TestQuiz_RuleEffects(t *testing.T) {
+	q := newTestQuiz([]string{"A mountain"},
+		[]string{"Mount Everest (tallest)"},
+		[]string{"Mount Everest (tallest)"},
+		[]string{"K2 (second)"},
+		[]string{"Fuji"},
+	)
+	err := q.SetRules(&Rules{Questions: map[string][]Rule{"1": {
+		{Find: `\s*\(.*\)`, Replace: ""},
+		{Find: `^Mount `, Replace: ""},
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	effects := q.RuleEffects()
+	want := [][]RuleChange{
+		{{"K2 (second)", "K2", 1}, {"Mount Everest (tallest)", "Mount Everest", 2}},
+		{{"Mount Everest", "Everest", 2}},
+	}
+	if len(effects) != len(want) {
+		t.Fatalf("RuleEffects() = %+v", effects)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(effects[i].Changes, want[i]) {
+			t.Errorf("rule %d changes = %+v, want %+v", i+1, effects[i].Changes, want[i])
+		}
+	}
+	if q.Responses[0].Answers[0] != "Mount Everest (tallest)" {
+		t.Errorf("RuleEffects() changed the answers")
+	}
+}
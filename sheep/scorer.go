@@ -0,0 +1,158 @@
+package sheep
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Scorer scores an answer to a question from the number of players who gave it
+type Scorer interface {
+	// Name is how the scorer is chosen, such as "herd:3"
+	Name() string
+	// Score returns the points for an answer which freq of the quiz's players gave
+	Score(freq int, q *Question, players int) int
+}
+
+// FrequencyScorer scores an answer with the number of players who gave it.  It is the
+// scorer unless another is chosen.
+type FrequencyScorer struct{}
+
+func (FrequencyScorer) Name() string { return "frequency" }
+
+func (FrequencyScorer) Score(freq int, q *Question, players int) int {
+	return freq
+}
+
+// ClassicScorer is classic Sheep: only the most frequent answer scores, with the number
+// of players who gave it.  Answers tied for the most frequent all score.
+type ClassicScorer struct{}
+
+func (ClassicScorer) Name() string { return "classic" }
+
+func (ClassicScorer) Score(freq int, q *Question, players int) int {
+	if freq < q.mostFreqAnswer() {
+		return 0
+	}
+	return freq
+}
+
+// HerdScorer scores an answer with the number of players who gave it, but only if at
+// least Min players gave it
+type HerdScorer struct {
+	Min int
+}
+
+func (h HerdScorer) Name() string { return fmt.Sprintf("herd:%d", h.Min) }
+
+func (h HerdScorer) Score(freq int, q *Question, players int) int {
+	if freq < h.Min {
+		return 0
+	}
+	return freq
+}
+
+// PercentScorer scores an answer with the percentage of players who gave it, rounded
+type PercentScorer struct{}
+
+func (PercentScorer) Name() string { return "percent" }
+
+func (PercentScorer) Score(freq int, q *Question, players int) int {
+	if players == 0 {
+		return 0
+	}
+	return (freq*200 + players) / (players * 2)
+}
+
+// ScorerNames lists the scorers which ParseScorer accepts
+const ScorerNames = "frequency, classic, herd:N, or percent"
+
+// ParseScorer returns the scorer with a name: frequency, classic, herd:N for a herd of at
+// least N players, or percent
+func ParseScorer(name string) (Scorer, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "frequency", "":
+		return FrequencyScorer{}, nil
+	case "classic":
+		return ClassicScorer{}, nil
+	case "percent":
+		return PercentScorer{}, nil
+	}
+	if min, ok := strings.CutPrefix(name, "herd:"); ok {
+		n, err := strconv.Atoi(min)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("herd size must be a number of at least 1, not '%s'", min)
+		}
+		return HerdScorer{Min: n}, nil
+	}
+	return nil, fmt.Errorf("unknown scoring '%s', must be %s", name, ScorerNames)
+}
+
+// SetScoring chooses the scorers of the quiz from a list such as "classic,3=herd:2,5=percent".
+// A scorer alone is used for the whole quiz, and one after a question number or text and
+// = only for that question.  Question text with a comma must be in double quotes, as in
+// "Red, white, or blue"=classic.
+func (q *Quiz) SetScoring(spec string) error {
+	list, err := splitQuoted(spec)
+	if err != nil {
+		return fmt.Errorf("scoring: %s", err)
+	}
+	for _, s := range list {
+		if len(strings.TrimSpace(s)) == 0 {
+			continue
+		}
+		key, name, perQuestion := s, s, false
+		if i := strings.LastIndex(s, "="); i >= 0 {
+			key, name, perQuestion = s[:i], s[i+1:], true
+		}
+		scorer, err := ParseScorer(name)
+		if err != nil {
+			return err
+		}
+		if !perQuestion {
+			q.Scorer = scorer
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if len(key) > 1 && strings.HasPrefix(key, `"`) && strings.HasSuffix(key, `"`) {
+			key = key[1 : len(key)-1]
+		}
+		idxQ, err := q.FindQuestion(key)
+		if err != nil {
+			return fmt.Errorf("scoring: %s", err)
+		}
+		q.Questions[idxQ].Scorer = scorer
+	}
+	return nil
+}
+
+// splitQuoted splits a list on the commas which are not in double quotes
+func splitQuoted(s string) ([]string, error) {
+	var list []string
+	start, quoted := 0, false
+	for i, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			list = append(list, s[start:i])
+			start = i + 1
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("missing closing quote in '%s'", s)
+	}
+	return append(list, s[start:]), nil
+}
+
+// QuestionScorer returns the scorer of a question: its own, the quiz's, or FrequencyScorer
+func (q *Quiz) QuestionScorer(idxQ int) Scorer {
+	if s := q.Questions[idxQ].Scorer; s != nil {
+		return s
+	}
+	if q.Scorer != nil {
+		return q.Scorer
+	}
+	return FrequencyScorer{}
+}
@@ -0,0 +1,123 @@
+package sheep
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// scorerQuiz has answers given by 3, 2, and 1 of 6 players, plus one tied for the most
+func scorerQuiz() *Quiz {
+	return newTestQuiz([]string{"A fruit", "A color"},
+		[]string{"Apple", "Red"},
+		[]string{"Apple", "Red"},
+		[]string{"Apple", "Red"},
+		[]string{"Pear", "Blue"},
+		[]string{"Pear", "Blue"},
+		[]string{"Kiwi", "Blue"},
+	)
+}
+
+// testScorer scores the answers of the first question of scorerQuiz: Apple, Pear, and Kiwi
+func testScorer(t *testing.T, s Scorer, want []int) {
+	t.Helper()
+	q := scorerQuiz()
+	q.Scorer = s
+	q.CalcScores()
+	var got []int
+	for _, r := range []int{0, 3, 5} {
+		got = append(got, q.Responses[r].AnswerScore[0])
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s scores = %v, want %v", s.Name(), got, want)
+	}
+}
+
+func TestFrequencyScorer(t *testing.T) {
+	testScorer(t, FrequencyScorer{}, []int{3, 2, 1})
+}
+
+func TestClassicScorer(t *testing.T) {
+	testScorer(t, ClassicScorer{}, []int{3, 0, 0})
+	q := scorerQuiz()
+	q.Scorer = ClassicScorer{}
+	q.CalcScores()
+	if red, blue := q.Responses[0].AnswerScore[1], q.Responses[3].AnswerScore[1]; red != 3 || blue != 3 {
+		t.Errorf("tied answers score %d and %d, want 3 and 3", red, blue)
+	}
+}
+
+func TestHerdScorer(t *testing.T) {
+	testScorer(t, HerdScorer{Min: 2}, []int{3, 2, 0})
+	testScorer(t, HerdScorer{Min: 3}, []int{3, 0, 0})
+}
+
+func TestPercentScorer(t *testing.T) {
+	testScorer(t, PercentScorer{}, []int{50, 33, 17})
+	if got := (PercentScorer{}).Score(1, &Question{}, 0); got != 0 {
+		t.Errorf("Score() with no players = %d, want 0", got)
+	}
+}
+
+func TestParseScorer(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Scorer
+		wantErr bool
+	}{
+		{"", FrequencyScorer{}, false},
+		{"frequency", FrequencyScorer{}, false},
+		{"Classic", ClassicScorer{}, false},
+		{"herd:3", HerdScorer{Min: 3}, false},
+		{"percent", PercentScorer{}, false},
+		{"herd:0", nil, true},
+		{"herd:x", nil, true},
+		{"median", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseScorer(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseScorer(%s) = %v, %v, want %v", tt.name, got, err, tt.want)
+		}
+		if err == nil {
+			if again, _ := ParseScorer(got.Name()); again != got {
+				t.Errorf("ParseScorer(%s) does not parse its Name()", tt.name)
+			}
+		}
+	}
+}
+
+func TestQuiz_SetScoring(t *testing.T) {
+	q := scorerQuiz()
+	if err := q.SetScoring("classic, A color=percent"); err != nil {
+		t.Fatal(err)
+	}
+	q.CalcScores()
+	if got := []int{q.Responses[3].AnswerScore[0], q.Responses[3].AnswerScore[1]}; !reflect.DeepEqual(got, []int{0, 50}) {
+		t.Errorf("scores = %v, want [0 50]", got)
+	}
+	filename := filepath.Join(t.TempDir(), "quiz.json")
+	if err := q.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+	var got Quiz
+	if err := got.ReadResponses(filename, ReadOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got.QuestionScorer(0) != (ClassicScorer{}) || got.QuestionScorer(1) != (PercentScorer{}) {
+		t.Errorf("scorers read = %v, %v", got.QuestionScorer(0), got.QuestionScorer(1))
+	}
+	for _, spec := range []string{"7=classic", "median", "1=herd:", `"A color=classic`} {
+		if err := q.SetScoring(spec); err == nil {
+			t.Errorf("SetScoring(%s) did not fail", spec)
+		}
+	}
+	// Question text with commas or = is quoted
+	q = newTestQuiz([]string{"Red, white, or blue", "2 + 2 = ?"}, []string{"Red", "4"})
+	if err := q.SetScoring(`percent, "Red, white, or blue"=classic,"2 + 2 = ?"=herd:2`); err != nil {
+		t.Fatal(err)
+	}
+	if q.QuestionScorer(0) != (ClassicScorer{}) || q.QuestionScorer(1) != (HerdScorer{Min: 2}) || q.Scorer != (PercentScorer{}) {
+		t.Errorf("scorers = %v, %v, %v", q.QuestionScorer(0), q.QuestionScorer(1), q.Scorer)
+	}
+}
@@ -0,0 +1,163 @@
+package sheep
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// teamSize is the number of members a full team is scored as having
+const teamSize = 4
+
+// MissingMode selects how the score of a missing team member is filled in
+type MissingMode string
+
+const (
+	MissingAvg    MissingMode = "avg"    // average of the members who responded
+	MissingLeast  MissingMode = "least"  // lowest score of the members who responded
+	MissingMiddle MissingMode = "middle" // median score of the members who responded
+)
+
+func ParseMissingMode(s string) (MissingMode, error) {
+	switch m := MissingMode(s); m {
+	case MissingAvg, MissingLeast, MissingMiddle:
+		return m, nil
+	}
+	return "", fmt.Errorf("missing member mode must be 'avg', 'least', or 'middle'")
+}
+
+type MemberScore struct {
+	Name  string
+	Email string
+	Score int
+}
+
+type TeamScore struct {
+	Name    string
+	Score   int
+	Members []MemberScore // Missing members are filled in with the name "--------"
+}
+
+// SortedCounts returns the answers to the question ordered by frequency, or alphabetically
+// by the original answer text when byResponse is set.  Answers with the same frequency are
+// ordered alphabetically so the order is always the same.
+func (q *Question) SortedCounts(byResponse bool) []PopulationCount {
+	a := make([]PopulationCount, 0, len(q.PopulationCounts))
+	for _, p := range q.PopulationCounts {
+		a = append(a, *p)
+	}
+	if byResponse {
+		sort.Slice(a, func(i, j int) bool {
+			return a[i].OriginalAnswer < a[j].OriginalAnswer
+		})
+	} else {
+		sort.Slice(a, func(i, j int) bool {
+			if a[i].Freq == a[j].Freq {
+				return a[i].OriginalAnswer < a[j].OriginalAnswer
+			}
+			return a[i].Freq > a[j].Freq
+		})
+	}
+	return a
+}
+
+// IsBonusAnswer reports whether the answer to a question earns the question's bonus.  The
+// answer matches the bonus answers the same way answers are grouped.
+func (q *Quiz) IsBonusAnswer(idxQ int, answer string) bool {
+	question := &q.Questions[idxQ]
+	if !question.BonusQuestion {
+		return false
+	}
+	key := q.AnswerKey(idxQ, answer)
+	for _, bonus := range question.BonusAnswers {
+		if q.AnswerKey(idxQ, bonus) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// PlayerScores returns the score of every response, highest first
+func (q *Quiz) PlayerScores() []MemberScore {
+	sortedScores := make([]MemberScore, 0, len(q.Responses))
+	for _, r := range q.Responses {
+		sortedScores = append(sortedScores, MemberScore{Name: r.Name, Email: r.Email, Score: r.TotalScore})
+	}
+	sort.Slice(sortedScores, func(i, j int) bool {
+		return sortedScores[i].Score > sortedScores[j].Score
+	})
+	return sortedScores
+}
+
+// MissingMembers returns the team members who did not submit a response
+func (q *Quiz) MissingMembers() []Member {
+	missing := make([]Member, 0)
+	for _, name := range q.Teams.Names() {
+		for _, member := range q.Teams[name] {
+			found := false
+			for _, r := range q.Responses {
+				if strings.EqualFold(r.Email, member.Email) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				missing = append(missing, member)
+			}
+		}
+	}
+	return missing
+}
+
+// TeamScores totals the member scores of each team, highest first.  Teams with fewer than
+// teamSize responses have the missing members scored according to the missing mode.
+func (q *Quiz) TeamScores(missingMemberMode MissingMode) []TeamScore {
+	sortedTeams := make([]TeamScore, 0, len(q.Teams))
+	for _, n := range q.Teams.Names() {
+		var ts TeamScore
+		membercount := 0
+		ts.Name = n
+		ts.Members = make([]MemberScore, 0, teamSize)
+		for _, r := range q.Responses {
+			if r.Team == n {
+				ts.Score += r.TotalScore
+				membercount++
+				ts.Members = append(ts.Members, MemberScore{
+					Name:  r.Name,
+					Email: r.Email,
+					Score: r.TotalScore,
+				})
+			}
+		}
+		sort.Slice(ts.Members, func(i, j int) bool {
+			return ts.Members[i].Score > ts.Members[j].Score
+		})
+		fillinscore := 0
+		if membercount > 0 && membercount < teamSize {
+			switch missingMemberMode {
+			case MissingAvg:
+				fillinscore = ts.Score / membercount
+			case MissingLeast:
+				fillinscore = ts.Members[len(ts.Members)-1].Score
+			case MissingMiddle:
+				switch len(ts.Members) {
+				case 0:
+					fillinscore = 0
+				case 1:
+					fillinscore = ts.Members[0].Score
+				case 2, 3:
+					fillinscore = ts.Members[1].Score
+				}
+			}
+			ts.Score += fillinscore * (teamSize - membercount)
+			for ; membercount < teamSize; membercount++ {
+				ts.Members = append(ts.Members, MemberScore{Score: fillinscore, Name: "--------"})
+			}
+		}
+		sortedTeams = append(sortedTeams, ts)
+	}
+	sort.Slice(sortedTeams, func(i, j int) bool {
+		return sortedTeams[i].Score > sortedTeams[j].Score
+	})
+	return sortedTeams
+}
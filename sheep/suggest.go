@@ -0,0 +1,309 @@
+package sheep
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultMergeThreshold is the similarity at which two answers are suggested as the same
+const DefaultMergeThreshold = 0.75
+
+// MergeGroup is a suggestion that some answers to a question are the same answer.  The
+// first answer, the most frequent, is the canonical one.
+type MergeGroup struct {
+	Question   int // index of the question
+	Answers    []PopulationCount
+	Confidence float64 // similarity of the least similar pair which joined the group, 0 to 1
+	Phonetic   bool    // some answers joined the group only because they sound alike
+}
+
+// SuggestOptions select which answers are suggested as the same
+type SuggestOptions struct {
+	Threshold float64 // similarity at which answers are the same
+	Phonetic  bool    // answers which sound alike are also the same
+	SoundOnly bool    // only answers which sound alike are the same, whatever the threshold
+}
+
+// Canonical is the answer the others in the group would become
+func (g *MergeGroup) Canonical() string {
+	return g.Answers[0].OriginalAnswer
+}
+
+// Variants are the answers which would become the canonical answer
+func (g *MergeGroup) Variants() []string {
+	v := make([]string, 0, len(g.Answers)-1)
+	for _, p := range g.Answers[1:] {
+		v = append(v, p.OriginalAnswer)
+	}
+	return v
+}
+
+// SuggestMerges clusters the answers to every question which are at least the threshold
+// similar, or sound alike, most confident first within each question.  CalcScores must
+// have been called.
+func (q *Quiz) SuggestMerges(opts SuggestOptions) []MergeGroup {
+	var groups []MergeGroup
+	for i := range q.Questions {
+		for _, g := range q.Questions[i].suggestMerges(q.canon(), opts) {
+			g.Question = i
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+// suggestMerges groups the answers to a question so that every pair of answers in a group
+// is similar enough, most alike first.  Answers sound alike when their words, made into
+// keys by the canonicalizer, have the same phonetic codes.
+func (q *Question) suggestMerges(canon *Canonicalizer, opts SuggestOptions) []MergeGroup {
+	answers := q.SortedCounts(false)
+	n := len(answers)
+	// link is the similarity of each pair of answers which may be in a group, or -1
+	link := make([][]float64, n)
+	sound := make([][]bool, n) // the pair may be in a group only because it sounds alike
+	tokens := make([]map[string]bool, n)
+	for i := range answers {
+		link[i], sound[i] = make([]float64, n), make([]bool, n)
+		tokens[i] = tokenSet(strings.ToLower(strings.TrimSpace(answers[i].OriginalAnswer)))
+	}
+	for i := range answers {
+		for j := i + 1; j < n; j++ {
+			sim := Similarity(answers[i].OriginalAnswer, answers[j].OriginalAnswer)
+			linked, soundAlike := sim >= opts.Threshold && !opts.SoundOnly, false
+			if !linked && (opts.Phonetic || opts.SoundOnly) {
+				if s := canon.SoundAlike(answers[i].OriginalAnswer, answers[j].OriginalAnswer); s > 0 {
+					sim, linked, soundAlike = maxFloat(sim, s), true, true
+				}
+			}
+			if !linked {
+				sim = -1
+			}
+			link[i][j], link[j][i] = sim, sim
+			sound[i][j], sound[j][i] = soundAlike, soundAlike
+		}
+	}
+	// A one word answer found in longer answers which differ, as "George" is in "George
+	// Washington" and "George Clooney", could be any of them, so it is only grouped with
+	// them when it is alike in more than the words they share
+	for i := range answers {
+		if len(tokens[i]) != 1 {
+			continue
+		}
+		var within []int
+		for j := range answers {
+			if j != i && len(tokens[j]) > 1 && link[i][j] >= 0 && !sound[i][j] && containment(tokens[i], tokens[j]) > 0 {
+				within = append(within, j)
+			}
+		}
+		if !anyUnlinked(link, within) {
+			continue
+		}
+		for _, j := range within {
+			a, b := strings.ToLower(strings.TrimSpace(answers[i].OriginalAnswer)), strings.ToLower(strings.TrimSpace(answers[j].OriginalAnswer))
+			if maxFloat(editSimilarity(a, b), tokenSetSimilarity(tokens[i], tokens[j])) < opts.Threshold {
+				link[i][j], link[j][i] = -1, -1
+			}
+		}
+	}
+	// Join the most alike groups until no two can be joined with every pair alike enough.
+	// The link between groups is that of their least alike pair.
+	members := make([][]int, n)
+	weakest := make([]float64, n)
+	for i := range members {
+		members[i] = []int{i}
+		weakest[i] = 1
+	}
+	for {
+		bestI, bestJ, best := -1, -1, -1.0
+		for i := range members {
+			for j := i + 1; j < n && members[i] != nil; j++ {
+				if members[j] != nil && link[i][j] > best {
+					bestI, bestJ, best = i, j, link[i][j]
+				}
+			}
+		}
+		if bestI < 0 {
+			break
+		}
+		members[bestI] = append(members[bestI], members[bestJ]...)
+		members[bestJ] = nil
+		weakest[bestI] = minFloat(minFloat(weakest[bestI], weakest[bestJ]), best)
+		for k := range members {
+			if link[bestI][k] < 0 || link[bestJ][k] < 0 {
+				link[bestI][k], link[k][bestI] = -1, -1
+			} else {
+				link[bestI][k] = minFloat(link[bestI][k], link[bestJ][k])
+				link[k][bestI] = link[bestI][k]
+			}
+		}
+	}
+	groups := make([]MergeGroup, 0)
+	for r, m := range members {
+		if len(m) < 2 {
+			continue
+		}
+		// The more frequent answers stay first in the group
+		sort.Ints(m)
+		g := MergeGroup{Confidence: weakest[r]}
+		for a, i := range m {
+			g.Answers = append(g.Answers, answers[i])
+			for _, j := range m[a+1:] {
+				g.Phonetic = g.Phonetic || sound[i][j]
+			}
+		}
+		pickCanonical(g.Answers)
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Confidence == groups[j].Confidence {
+			return groups[i].Canonical() < groups[j].Canonical()
+		}
+		return groups[i].Confidence > groups[j].Confidence
+	})
+	return groups
+}
+
+// anyUnlinked reports whether any two of the answers may not be in a group together
+func anyUnlinked(link [][]float64, answers []int) bool {
+	for a, i := range answers {
+		for _, j := range answers[a+1:] {
+			if link[i][j] < 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pickCanonical moves the canonical answer of a group to the front.  It is the most
+// frequent answer, or of the answers tied for most frequent, the one most like the others.
+func pickCanonical(m []PopulationCount) {
+	best, bestSim := 0, -1.0
+	for i := range m {
+		if m[i].Freq < m[0].Freq {
+			break
+		}
+		sim := 0.0
+		for j := range m {
+			if i != j {
+				sim += Similarity(m[i].OriginalAnswer, m[j].OriginalAnswer)
+			}
+		}
+		if sim > bestSim {
+			best, bestSim = i, sim
+		}
+	}
+	m[0], m[best] = m[best], m[0]
+}
+
+// Similarity scores how alike two answers are from 0 to 1, taking the best of the edit
+// distance, the overlap of their words, and whether the words of one are all in the other
+func Similarity(a, b string) float64 {
+	a, b = strings.ToLower(strings.TrimSpace(a)), strings.ToLower(strings.TrimSpace(b))
+	if a == b {
+		return 1
+	}
+	ta, tb := tokenSet(a), tokenSet(b)
+	return maxFloat(editSimilarity(a, b), maxFloat(tokenSetSimilarity(ta, tb), containment(ta, tb)))
+}
+
+// editSimilarity is 1 less the edit distance as a fraction of the longer answer
+func editSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein counts the insertions, deletions, and substitutions to turn a into b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// tokenSet splits an answer into its distinct words, ignoring punctuation
+func tokenSet(s string) map[string]bool {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
+
+// tokenSetSimilarity is the number of words in both answers over the number in either
+func tokenSetSimilarity(a, b map[string]bool) float64 {
+	both := commonTokens(a, b)
+	either := len(a) + len(b) - both
+	if either == 0 {
+		return 0
+	}
+	return float64(both) / float64(either)
+}
+
+// containment scores an answer whose words are all in the other answer, as in "Stevia" and
+// "Stevia sweetener".  It is kept below 1 since the longer answer may be more specific.
+func containment(a, b map[string]bool) float64 {
+	shorter, longer := len(a), len(b)
+	if shorter > longer {
+		shorter, longer = longer, shorter
+	}
+	if shorter == 0 || commonTokens(a, b) < shorter {
+		return 0
+	}
+	return 0.8 + 0.1*float64(shorter)/float64(longer)
+}
+
+func commonTokens(a, b map[string]bool) int {
+	n := 0
+	for w := range a {
+		if b[w] {
+			n++
+		}
+	}
+	return n
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
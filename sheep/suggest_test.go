@@ -0,0 +1,93 @@
+package sheep
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		atLeast float64
+		below   float64
+	}{
+		{"Stevia", "Steevia", 0.85, 0.86},
+		{"Stevia", "stevia ", 1, 1.01},
+		{"Stevia", "Stevia sweetener", 0.85, 0.86},
+		{"Sweet and Low", "Low and Sweet", 1, 1.01},
+		{"New York Yankees", "Yankees", 0.83, 0.84},
+		{"Sugar", "Honey", 0, 0.5},
+		{"Red", "Rod", 0.66, 0.67},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			got := Similarity(tt.a, tt.b)
+			if got < tt.atLeast || got >= tt.below {
+				t.Errorf("Similarity() = %.3f, want [%.2f, %.2f)", got, tt.atLeast, tt.below)
+			}
+			if rev := Similarity(tt.b, tt.a); rev != got {
+				t.Errorf("Similarity() reversed = %.3f, want %.3f", rev, got)
+			}
+		})
+	}
+}
+
+func TestQuiz_SuggestMerges(t *testing.T) {
+	q := newTestQuiz([]string{"A sweetener", "A color"},
+		[]string{"Stevia", "Red"},
+		[]string{"Steevia", "Red"},
+		[]string{"Stevia sweetener", "Blue"},
+		[]string{"Sugar", "Green"},
+		[]string{"Honey", "Greene"},
+		[]string{"Sugar", "Greene"},
+		[]string{"Stevia", "Red"},
+	)
+	q.CalcScores()
+	groups := q.SuggestMerges(SuggestOptions{Threshold: DefaultMergeThreshold})
+	if len(groups) != 2 {
+		t.Fatalf("SuggestMerges() = %+v", groups)
+	}
+	if g := groups[0]; g.Question != 0 || g.Canonical() != "Stevia" ||
+		!reflect.DeepEqual(g.Variants(), []string{"Steevia"}) || g.Confidence < 0.85 {
+		t.Errorf("group 1 = %+v", g)
+	}
+	if g := groups[1]; g.Question != 1 || g.Canonical() != "Greene" || !reflect.DeepEqual(g.Variants(), []string{"Green"}) {
+		t.Errorf("group 2 = %+v", g)
+	}
+	if groups := q.SuggestMerges(SuggestOptions{Threshold: 0.9}); len(groups) != 0 {
+		t.Errorf("SuggestMerges(0.9) = %+v", groups)
+	}
+}
+
+func TestQuiz_SuggestMerges_linkage(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers []string
+		want    [][]string // canonical answer and variants of each group
+	}{
+		{"two Georges", []string{"George Washington", "George Washington", "George Clooney", "George"}, nil},
+		{"one George", []string{"George Washington", "George Washington", "George"}, [][]string{{"George Washington", "George"}}},
+		// Steevia is like Stevia but not like Stevia sweetener, so they are not all one group
+		{"every pair", []string{"Stevia", "Stevia", "Steevia", "Stevia sweetener"}, [][]string{{"Stevia", "Steevia"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var responses [][]string
+			for _, a := range tt.answers {
+				responses = append(responses, []string{a})
+			}
+			q := newTestQuiz([]string{"Someone named George"}, responses...)
+			q.CalcScores()
+			var got [][]string
+			for _, g := range q.SuggestMerges(SuggestOptions{Threshold: DefaultMergeThreshold}) {
+				got = append(got, append([]string{g.Canonical()}, g.Variants()...))
+				if g.Confidence < DefaultMergeThreshold {
+					t.Errorf("group %v confidence = %.2f", g.Answers, g.Confidence)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SuggestMerges() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
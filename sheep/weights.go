@@ -0,0 +1,98 @@
+package sheep
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// weightMarker starts the weight of a question in its title, as in "×2 The final question"
+// or "×0.5 Lightning round: a color"
+const weightMarker = '×'
+
+// titleMarkers returns the markers at the start of a question's title and the weight given
+// by any weight marker, or 0 if there is none.  A × with no number after it is part of the
+// title, as in "× marks the spot".
+func titleMarkers(text string) (markers string, weight float64, err error) {
+	rest := text
+	for {
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		r, size := utf8.DecodeRuneInString(rest)
+		switch r {
+		case '🎯', numericMarker, multiMarker:
+			rest = rest[size:]
+		case weightMarker:
+			n := strings.IndexFunc(rest[size:], func(r rune) bool { return !unicode.IsDigit(r) && r != '.' })
+			if n < 0 {
+				n = len(rest) - size
+			}
+			if !strings.ContainsAny(rest[size:size+n], "0123456789") {
+				return text[:len(text)-len(rest)], weight, nil
+			}
+			if weight, err = strconv.ParseFloat(rest[size:size+n], 64); err != nil || weight <= 0 {
+				return "", 0, fmt.Errorf("question weight '%s' must be a positive number", rest[:size+n])
+			}
+			rest = rest[size+n:]
+		default:
+			return text[:len(text)-len(rest)], weight, nil
+		}
+	}
+}
+
+// trimMarkers removes the markers from the start of a question's title
+func trimMarkers(text string) string {
+	markers, _, err := titleMarkers(text)
+	if err != nil {
+		return text
+	}
+	return strings.TrimSpace(text[len(markers):])
+}
+
+// Weighted reports whether the question's scores are weighted
+func (q *Question) Weighted() bool {
+	return q.Weight > 0 && q.Weight != 1
+}
+
+// weigh returns a score with the question's weight, rounded
+func (q *Question) weigh(score int) int {
+	if !q.Weighted() {
+		return score
+	}
+	return int(math.Round(float64(score) * q.Weight))
+}
+
+// WeightsFileName is the weights file kept next to a response file: responses.xlsx has
+// responses.weights.json.
+func WeightsFileName(filename string) string {
+	return sidecarFile(filename, ".weights.json")
+}
+
+// ReadWeights reads a weights file, which maps questions by number, starting at 1, or by
+// text to their weights.  A file which does not exist has no weights.
+//
+//	{"10": 2, "Lightning round: a color": 0.5}
+func ReadWeights(filename string) (map[string]float64, error) {
+	weights := make(map[string]float64)
+	if err := readJSONFile(filename, &weights); err != nil {
+		return nil, err
+	}
+	return weights, nil
+}
+
+// SetWeights sets the weights of questions, replacing any set by their titles
+func (q *Quiz) SetWeights(weights map[string]float64) error {
+	for key, w := range weights {
+		idxQ, err := q.FindQuestion(key)
+		if err != nil {
+			return fmt.Errorf("weights: %s", err)
+		}
+		if w <= 0 {
+			return fmt.Errorf("weights: question %s has weight %g, which is not positive", key, w)
+		}
+		q.Questions[idxQ].Weight = w
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package sheep
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func Test_buildQuestion_weight(t *testing.T) {
+	tests := []struct {
+		title   string
+		weight  float64
+		numeric bool
+		bonus   bool
+		wantErr bool
+	}{
+		{"A color", 0, false, false, false},
+		{"×2 The final question", 2, false, false, false},
+		{"×0.5 🔢 Lightning round: a number", 0.5, true, false, false},
+		{"🎯×3 A fruit [Kiwi]", 3, false, true, false},
+		{"Colors × 2", 0, false, false, false},
+		{"×0 A color", 0, false, false, true},
+		{"× marks the spot", 0, false, false, false},
+		{"🔢× marks the spot", 0, true, false, false},
+		{"×. A color", 0, false, false, false},
+		{"×1.2.3 A color", 0, false, false, true},
+	}
+	for _, tt := range tests {
+		q, err := buildQuestion(tt.title)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("buildQuestion(%s) error = %v, wantErr %v", tt.title, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (q.Weight != tt.weight || q.Numeric != tt.numeric || q.BonusQuestion != tt.bonus) {
+			t.Errorf("buildQuestion(%s) = weight %g, numeric %v, bonus %v", tt.title, q.Weight, q.Numeric, q.BonusQuestion)
+		}
+	}
+}
+
+func TestQuiz_CalcScores_weights(t *testing.T) {
+	q := newTestQuiz([]string{"A color", "×2 🎯 A fruit [Kiwi]", "×0.5 A number"},
+		[]string{"Red", "Apple", "7"},
+		[]string{"Red", "Apple", "7"},
+		[]string{"Blue", "Kiwi", "3"},
+	)
+	q.CalcScores()
+	a, c := q.Responses[0], q.Responses[2]
+	if a.AnswerScore[1] != 4 || a.RawScore[1] != 2 || a.AnswerScore[2] != 1 || a.RawScore[2] != 2 || a.TotalScore != 2+4+1 {
+		t.Errorf("scores = %v, raw %v, total %d", a.AnswerScore, a.RawScore, a.TotalScore)
+	}
+	// The bonus of 3 is doubled, and half of 1 rounds to 1
+	if c.AnswerScore[1] != 6 || c.TotalBonus != 6 || c.AnswerScore[2] != 1 {
+		t.Errorf("scores = %v, bonus %d", c.AnswerScore, c.TotalBonus)
+	}
+}
+
+func TestQuiz_SetWeights(t *testing.T) {
+	filename := writeTestFile(t, "quiz.weights.json", `{"2": 2, "A color": 0.5}`)
+	weights, err := ReadWeights(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := newTestQuiz([]string{"×3 A color", "A fruit"}, []string{"Red", "Apple"})
+	if err = q.SetWeights(weights); err != nil {
+		t.Fatal(err)
+	}
+	if q.Questions[0].Weight != 0.5 || q.Questions[1].Weight != 2 {
+		t.Errorf("weights = %g, %g, want 0.5, 2", q.Questions[0].Weight, q.Questions[1].Weight)
+	}
+	out := filepath.Join(t.TempDir(), "quiz.json")
+	if err = q.Save(out); err != nil {
+		t.Fatal(err)
+	}
+	var got Quiz
+	if err = got.ReadResponses(out, ReadOptions{}); err != nil || got.Questions[1].Weight != 2 {
+		t.Errorf("weight read = %v, %v", got.Questions, err)
+	}
+	for _, bad := range []map[string]float64{{"3": 2}, {"1": 0}} {
+		if err = q.SetWeights(bad); err == nil {
+			t.Errorf("SetWeights(%v) did not fail", bad)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"slices"
+
+	"github.com/jjcinaz/sheeptabulator/sheep"
+)
+
+// runValidate reads a quiz the same way score does but reports every problem it finds
+// instead of stopping at the first one.  Problems which would stop scoring are errors;
+// the rest are warnings, which only fail validation with -strict.
+func runValidate(fset *flag.FlagSet, args []string) int {
+	var (
+		qf       quizFlags
+		quiz     sheep.Quiz
+		errs     int
+		warnings int
+	)
+	qf.register(fset)
+	strict := fset.Bool("strict", false, "Treat warnings as errors")
+	if code := parseFlags(fset, args); code >= 0 {
+		return code
+	}
+	if !qf.requireFile(fset) {
+		return exitUsage
+	}
+	opts, err := qf.readOptions()
+	if err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	if quiz.Canon, err = qf.canonicalizer(); err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	teams, err := qf.readTeams()
+	if err != nil {
+		fmt.Println(err)
+		return exitError
+	}
+	for _, filename := range qf.filenames {
+		before := len(quiz.Responses)
+		if err = quiz.AddResponses(filename, opts); err != nil {
+			fmt.Println(err)
+			if errors.Is(err, fs.ErrNotExist) {
+				return exitError
+			}
+			return exitInvalid
+		}
+		fmt.Printf("Read %d questions and %d responses from %s\n", len(quiz.Questions), len(quiz.Responses)-before, filename)
+	}
+	for i, q := range quiz.Questions {
+		if q.BonusQuestion && slices.Contains(q.BonusAnswers, "") {
+			fmt.Printf("warning: question #%d is a bonus question with a blank bonus answer\n", i+1)
+			warnings++
+		}
+	}
+	if teams != nil {
+		for _, r := range quiz.Responses {
+			if _, err := teams.FindTeam(r.Email); err != nil {
+				fmt.Printf("error: %s\n", err)
+				errs++
+			}
+		}
+	}
+	for _, r := range quiz.EliminateDups() {
+		fmt.Printf("warning: duplicate response from %s in %s completed %s will be ignored\n", r.Email, r.Source, r.Completed.Format("2006-01-02 15:04:05"))
+		warnings++
+	}
+	if err = quiz.SetScoring(qf.scoring); err != nil {
+		fmt.Printf("error: %s\n", err)
+		errs++
+	}
+	if err = setWeights(&quiz, qf.weightsFile()); err != nil {
+		fmt.Printf("error: %s\n", err)
+		errs++
+	}
+	// A check writes no files, so there is no report
+	if err = moderate(&quiz, qf.blocklist, qf.moderationFile(), ""); err != nil {
+		fmt.Printf("error: %s\n", err)
+		errs++
+	}
+	if err = setRules(&quiz, qf.rulesFile()); err != nil {
+		fmt.Printf("error: %s\n", err)
+		errs++
+	}
+	if err = setDictionary(&quiz, qf.dict); err != nil {
+		fmt.Printf("error: %s\n", err)
+		errs++
+	}
+	if aliases, err := applyAliases(&quiz, qf.aliasFile()); err != nil {
+		fmt.Printf("error: %s\n", err)
+		errs++
+	} else {
+		for _, key := range sortedKeys(aliases.Flagged) {
+			idxQ, err := quiz.FindQuestion(key)
+			if err != nil {
+				fmt.Printf("error: flagged answers: %s\n", err)
+				errs++
+				continue
+			}
+			for _, answer := range aliases.Flagged[key] {
+				fmt.Printf("warning: answer '%s' to question #%d is flagged for review\n", answer, idxQ+1)
+				warnings++
+			}
+		}
+	}
+	for _, r := range quiz.Responses {
+		blank := 0
+		for _, a := range r.Answers {
+			if len(a) == 0 {
+				blank++
+			}
+		}
+		blank += len(quiz.Questions) - len(r.Answers)
+		if blank > 0 {
+			fmt.Printf("warning: %s left %d of %d questions blank\n", r.Email, blank, len(quiz.Questions))
+			warnings++
+		}
+	}
+	if teams != nil {
+		quiz.Teams = teams
+		for _, member := range quiz.MissingMembers() {
+			fmt.Printf("warning: missing response from %s on team %s\n", member.Email, member.Team)
+			warnings++
+		}
+	}
+	fmt.Printf("%d errors, %d warnings\n", errs, warnings)
+	if errs > 0 || (*strict && warnings > 0) {
+		return exitInvalid
+	}
+	return exitOK
+}